@@ -0,0 +1,258 @@
+// Package console reads command lines from a terminal, with line editing,
+// command history and tab completion.  When the input isn't a terminal it
+// simply reads lines.
+package console
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// ErrInterrupt is returned by ReadLine when the user types Ctrl-C.
+var ErrInterrupt = errors.New("interrupted")
+
+// Console reads lines from an input file, echoing to an output writer.
+type Console struct {
+	// Complete, if set, returns the possible completions of a partly typed
+	// line, each as a whole line.
+	Complete func(line string) []string
+	// History holds the lines read so far, oldest first.
+	History []string
+
+	in     *os.File
+	out    io.Writer
+	reader *bufio.Reader
+}
+
+// New makes a Console that reads from in and echoes to out.
+func New(in *os.File, out io.Writer) *Console {
+	return &Console{in: in, out: out, reader: bufio.NewReader(in)}
+}
+
+// ReadLine shows the prompt and reads a line, without its line ending.  It
+// returns io.EOF when the input ends or the user types Ctrl-D on an empty
+// line.
+func (c *Console) ReadLine(prompt string) (string, error) {
+	fmt.Fprint(c.out, prompt)
+	restore, err := makeRaw(int(c.in.Fd()))
+	if err != nil {
+		// not a terminal
+		return c.readCooked()
+	}
+	defer restore()
+	line, err := c.edit(prompt)
+	fmt.Fprint(c.out, "\r\n")
+	if err == nil {
+		c.remember(line)
+	}
+	return line, err
+}
+
+// readCooked reads a line from input that isn't a terminal.
+func (c *Console) readCooked() (string, error) {
+	s, err := c.reader.ReadString('\n')
+	if err == io.EOF && s != "" {
+		err = nil
+	}
+	if err != nil {
+		return "", err
+	}
+	line := strings.TrimRight(s, "\r\n")
+	c.remember(line)
+	return line, nil
+}
+
+func (c *Console) remember(line string) {
+	if strings.TrimSpace(line) == "" {
+		return
+	}
+	if n := len(c.History); n > 0 && c.History[n-1] == line {
+		return
+	}
+	c.History = append(c.History, line)
+}
+
+// Control keys understood while editing.
+const (
+	keyCtrlA     = 1
+	keyCtrlC     = 3
+	keyCtrlD     = 4
+	keyCtrlE     = 5
+	keyBackspace = 8
+	keyTab       = 9
+	keyLF        = 10
+	keyCtrlK     = 11
+	keyCR        = 13
+	keyCtrlU     = 21
+	keyEscape    = 27
+	keyDelete    = 127
+)
+
+// editor is the state of a line being edited.
+type editor struct {
+	c       *Console
+	prompt  string
+	line    []rune
+	pos     int // cursor position in line
+	history int // index into History of the line shown; len(History) for a new line
+	pending string
+}
+
+// edit reads and edits a line in raw mode.
+func (c *Console) edit(prompt string) (string, error) {
+	// only the last line of the prompt is redrawn
+	prompt = prompt[strings.LastIndex(prompt, "\n")+1:]
+	e := &editor{c: c, prompt: prompt, history: len(c.History)}
+	for {
+		r, _, err := c.reader.ReadRune()
+		if err != nil {
+			return "", err
+		}
+		switch r {
+		case keyCR, keyLF:
+			return string(e.line), nil
+		case keyCtrlC:
+			return "", ErrInterrupt
+		case keyCtrlD:
+			if len(e.line) == 0 {
+				return "", io.EOF
+			}
+			e.delete()
+		case keyBackspace, keyDelete:
+			if e.pos > 0 {
+				e.pos--
+				e.delete()
+			}
+		case keyCtrlA:
+			e.pos = 0
+		case keyCtrlE:
+			e.pos = len(e.line)
+		case keyCtrlK:
+			e.line = e.line[:e.pos]
+		case keyCtrlU:
+			e.line = e.line[e.pos:]
+			e.pos = 0
+		case keyTab:
+			e.complete()
+		case keyEscape:
+			e.escape()
+		default:
+			if r >= ' ' {
+				e.line = append(e.line[:e.pos], append([]rune{r}, e.line[e.pos:]...)...)
+				e.pos++
+			}
+		}
+		e.redraw()
+	}
+}
+
+// escape handles the escape sequences sent by the arrow, home, end and
+// delete keys.
+func (e *editor) escape() {
+	r, _, err := e.c.reader.ReadRune()
+	if err != nil || (r != '[' && r != 'O') {
+		return
+	}
+	r, _, err = e.c.reader.ReadRune()
+	if err != nil {
+		return
+	}
+	switch r {
+	case 'A':
+		e.recall(-1)
+	case 'B':
+		e.recall(1)
+	case 'C':
+		if e.pos < len(e.line) {
+			e.pos++
+		}
+	case 'D':
+		if e.pos > 0 {
+			e.pos--
+		}
+	case 'H':
+		e.pos = 0
+	case 'F':
+		e.pos = len(e.line)
+	case '3':
+		if r, _, _ = e.c.reader.ReadRune(); r == '~' {
+			e.delete()
+		}
+	}
+}
+
+// delete deletes the character under the cursor.
+func (e *editor) delete() {
+	if e.pos < len(e.line) {
+		e.line = append(e.line[:e.pos], e.line[e.pos+1:]...)
+	}
+}
+
+// recall replaces the line with an earlier or later one from the history.
+func (e *editor) recall(dir int) {
+	h := e.c.History
+	n := e.history + dir
+	if n < 0 || n > len(h) {
+		return
+	}
+	if e.history == len(h) {
+		e.pending = string(e.line)
+	}
+	e.history = n
+	if n == len(h) {
+		e.line = []rune(e.pending)
+	} else {
+		e.line = []rune(h[n])
+	}
+	e.pos = len(e.line)
+}
+
+// complete completes the text before the cursor as far as it can, listing
+// the choices if there's more than one.
+func (e *editor) complete() {
+	if e.c.Complete == nil {
+		return
+	}
+	before := string(e.line[:e.pos])
+	choices := e.c.Complete(before)
+	if len(choices) == 0 {
+		return
+	}
+	prefix := choices[0]
+	for _, ch := range choices[1:] {
+		prefix = commonPrefix(prefix, ch)
+	}
+	if len(choices) == 1 {
+		prefix += " "
+	}
+	if len(prefix) > len(before) {
+		rest := e.line[e.pos:]
+		e.line = append([]rune(prefix), rest...)
+		e.pos = len([]rune(prefix))
+		return
+	}
+	fmt.Fprint(e.c.out, "\r\n"+strings.Join(choices, "   ")+"\r\n")
+}
+
+// commonPrefix returns the longest prefix of a that b shares, ignoring case.
+func commonPrefix(a, b string) string {
+	ra, rb := []rune(a), []rune(b)
+	n := 0
+	for n < len(ra) && n < len(rb) && strings.EqualFold(string(ra[n]), string(rb[n])) {
+		n++
+	}
+	return string(ra[:n])
+}
+
+// redraw rewrites the prompt and line, leaving the cursor in place.
+func (e *editor) redraw() {
+	s := "\r" + e.prompt + string(e.line) + "\x1b[K"
+	if back := len(e.line) - e.pos; back > 0 {
+		s += fmt.Sprintf("\x1b[%dD", back)
+	}
+	fmt.Fprint(e.c.out, s)
+}
@@ -0,0 +1,11 @@
+//go:build !linux
+
+package console
+
+import "errors"
+
+// makeRaw is only supported on Linux; elsewhere the console reads whole
+// lines without editing.
+func makeRaw(fd int) (func(), error) {
+	return nil, errors.New("raw terminal mode not supported")
+}
@@ -3,6 +3,7 @@ package mb
 import (
 	"errors"
 	"fmt"
+	"sort"
 	"strings"
 )
 
@@ -25,6 +26,18 @@ const (
 	EnemyTarget
 )
 
+func (t TargetType) String() string {
+	switch t {
+	case WarpathTarget:
+		return "warpath"
+	case LandTarget:
+		return "land"
+	case EnemyTarget:
+		return "enemy"
+	}
+	return ""
+}
+
 // Actions implement mb.state. Actions that can be taken on a warpath
 // implement mb.warpathAction.
 type PeacePipeAction int
@@ -34,11 +47,19 @@ type FortifyAction int
 type AttackAction int
 type RepairAction int
 type PowwowAction int
+type PassAction int
 type QuitAction int
 
 // warpathAction is used to indicate whether the action can currently be performed on the warpath.
+// checkWarpath returns the reason it can't, or nil if it can.
 type warpathAction interface {
-	isEnabledOnWarpath(g *Game, t Tribe) bool
+	checkWarpath(g *Game, t Tribe) error
+}
+
+// checkedAction is implemented by actions that can tell whether they could be
+// performed on a target without performing them.
+type checkedAction interface {
+	check(g *Game, target interface{}) error
 }
 
 type ActionCost int
@@ -51,6 +72,16 @@ const (
 	PalisadeValueCost
 )
 
+func (c ActionCost) String() string {
+	switch c {
+	case ChiefdomValueCost:
+		return "chiefdom value"
+	case PalisadeValueCost:
+		return "palisade value"
+	}
+	return fmt.Sprintf("%d", int(c))
+}
+
 var actions = []ActionSpec{
 	ActionSpec{"ppa", "Peace Pipe", "Unopposed Peace Pipe advance", PeacePipeAction(0), WarpathTarget, OneCost},
 	ActionSpec{"inc", "Incorporate", "Incorporate a Chiefdom", IncorporateAction(0), WarpathTarget, OneCost},
@@ -59,6 +90,7 @@ var actions = []ActionSpec{
 	ActionSpec{"att", "Attack", "Attack Hostile Army", AttackAction(0), EnemyTarget, OneCost},
 	ActionSpec{"rep", "Repair", "Repair Breach", RepairAction(0), NoTarget, PalisadeValueCost},
 	ActionSpec{"pow", "Powwow", "Powwow", PowwowAction(0), WarpathTarget, TwoCost},
+	ActionSpec{"pas", "Pass", "End the Action Phase", PassAction(0), NoTarget, ZeroCost},
 	ActionSpec{"qui", "Quit", "Quit the Game", QuitAction(0), NoTarget, ZeroCost},
 }
 
@@ -75,6 +107,52 @@ type FrontEndAction struct {
 	ActionSpec
 	IsAvailable bool
 	ActualCost int
+	Reason      string    `json:",omitempty"` // why the action isn't available
+	ReasonCode  ErrorCode `json:",omitempty"`
+}
+
+// specFor finds the ActionSpec for an action type.
+func specFor(s state) *ActionSpec {
+	for i := range actions {
+		if actions[i].Type == s {
+			return &actions[i]
+		}
+	}
+	return nil
+}
+
+// actionCost works out how many APs an action costs when aimed at the given
+// target.  It returns an error if the cost depends on the target and the
+// target doesn't have one.
+func (g *Game) actionCost(as *ActionSpec, target interface{}) (int, error) {
+	switch as.Cost {
+	case ChiefdomValueCost:
+		l, ok := target.(Land)
+		if !ok {
+			return 0, errWrongTarget(as, "land")
+		}
+		c := g.Board.Chiefdoms[l.Index]
+		if c == nil {
+			return 0, errNoChiefdom(l)
+		}
+		return c.getValue(), nil
+	case PalisadeValueCost:
+		return g.Board.palisade().Value, nil
+	}
+	return int(as.Cost), nil
+}
+
+// checkCost works out an action's cost, returning an error if it can't be
+// worked out or if it's more than the APs remaining.
+func (g *Game) checkCost(as *ActionSpec, target interface{}) (int, error) {
+	cost, err := g.actionCost(as, target)
+	if err != nil {
+		return 0, err
+	}
+	if avail := g.Board.ActionPoints; cost > avail {
+		return cost, errInsufficientAP(cost, avail)
+	}
+	return cost, nil
 }
 
 func (g *Game) availableWarpathActions() map[string][]FrontEndAction {
@@ -82,23 +160,40 @@ func (g *Game) availableWarpathActions() map[string][]FrontEndAction {
 	for _, t := range tribes {
 		for _, s := range actions {
 			if at, ok := s.Type.(warpathAction); ok {
-					f := FrontEndAction{s, at.isEnabledOnWarpath(g, t), 0}
-					f.ActualCost = int(s.Cost)						
-					result[tribeNames[t]] = append(result[tribeNames[t]], f)
-				}
+				f := FrontEndAction{ActionSpec: s}
+				f.Reason, f.ReasonCode = reason(at.checkWarpath(g, t))
+				f.IsAvailable = f.Reason == ""
+				f.ActualCost, _ = g.actionCost(&s, t)
+				result[tribeNames[t]] = append(result[tribeNames[t]], f)
 			}
 		}
+	}
 	return result
 }
 
 func (g *Game) markBuildableChiefdoms() {
 	for _, c := range g.Board.Chiefdoms {
 		if c != nil {
-			c.CanBuild = BuildAction(0).isEnabledForChiefdom(g, *c)
+			c.BuildReason, c.BuildReasonCode = reason(BuildAction(0).checkChiefdom(g, *c))
+			c.CanBuild = c.BuildReason == ""
+			c.BuildCost, _ = g.actionCost(specFor(BuildAction(0)), g.Board.Lands[c.LandIndex])
 		}
 	}
 }
 
+// reason returns the message and code of the error that makes an action
+// unavailable, or blanks if it's available.
+func reason(err error) (string, ErrorCode) {
+	if err == nil {
+		return "", ""
+	}
+	var re *RuleError
+	if errors.As(err, &re) {
+		return re.Message, re.Code
+	}
+	return err.Error(), ""
+}
+
 // finder is a function that finds a unique game object given its prefix.
 type finder func(string, *Game) (interface{}, error)
 
@@ -109,18 +204,20 @@ var findFunction = map[TargetType]finder{
 	EnemyTarget:   findEnemy,
 }
 
+// parseAction parses a command: an action, by code, name or alias, followed
+// by its target, if it has one.  The target can be more than one word.
 func (g *Game) parseAction(c string) (*Action, error) {
-	tokens := strings.Split(c, " ")
-	as, err := findActionSpec(tokens[0])
+	tokens, err := tokenize(c)
 	if err != nil {
 		return nil, err
 	}
-	if len(tokens) < 2 && as.Target != NoTarget {
-		return nil, fmt.Errorf("The %s action requires a target.", as.Description)
+	as, n, err := findActionSpec(tokens)
+	if err != nil {
+		return nil, err
 	}
-	t := ""
-	if len(tokens) > 1 {
-		t = strings.ToLower(tokens[1])
+	t := strings.Join(tokens[n:], " ")
+	if t == "" && as.Target != NoTarget {
+		return nil, errMissingTarget(&as)
 	}
 	target, err := findFunction[as.Target](t, g)
 	if err != nil {
@@ -129,16 +226,6 @@ func (g *Game) parseAction(c string) (*Action, error) {
 	return &Action{Spec: &as, Target: target}, nil
 }
 
-func findActionSpec(token string) (ActionSpec, error) {
-	t := strings.ToLower(token)
-	for _, as := range actions {
-		if as.Name == t {
-			return as, nil
-		}
-	}
-	return ActionSpec{}, fmt.Errorf("Unknown action: %q", t)
-}
-
 func findNothing(string, *Game) (interface{}, error) {
 	return "", nil
 }
@@ -150,48 +237,158 @@ func findWarpath(t string, g *Game) (interface{}, error) {
 		return nil, err
 	}
 	if tribe.(Tribe) > Caddo {
-		return nil, fmt.Errorf("%q doesn't match a warpath.", t)
+		return nil, errTargetNotFound("a warpath", t, nil)
 	}
 	return tribe, nil
 }
 
-// findLand finds a land, given its abbreviation.
+// findLand finds a land, given its name or an abbreviation of it.
 func findLand(t string, g *Game) (interface{}, error) {
-	found := make([]Land, 0)
-	for _, land := range g.Board.Lands {
-		if strings.HasPrefix(strings.ToLower(land.Name), t) {
-			found = append(found, land)
-		}
+	names := make([]string, len(g.Board.Lands))
+	for i, land := range g.Board.Lands {
+		names[i] = land.Name
 	}
+	found := bestMatches(names, t)
 	switch {
 	case len(found) == 0:
-		return nil, fmt.Errorf("%q doesn't match a land.", t)
+		return nil, errTargetNotFound("a land", t, suggest(t, names))
 	case len(found) > 1:
-		return nil, fmt.Errorf("%q matches more than one land.", t)
+		var matches []string
+		for _, i := range found {
+			matches = append(matches, names[i])
+		}
+		return nil, errAmbiguousTarget("land", t, matches)
 	}
-	return found[0], nil
+	return g.Board.Lands[found[0]], nil
 }
 
 // findEnemy finds an enemy - either a tribe or the Spanish.
 func findEnemy(t string, _ *Game) (interface{}, error) {
-	found := make([]Tribe, 0)
+	var names []string
 	for k, v := range tribeNameLookup {
-		if v > SpanishTribe {
-			continue
-		}
-		if strings.HasPrefix(strings.ToLower(k), t) {
-			found = append(found, v)
+		if v <= SpanishTribe {
+			names = append(names, k)
 		}
 	}
+	sort.Strings(names)
+	found := bestMatches(names, t)
 	switch {
 	case len(found) == 0:
-		return nil, fmt.Errorf("%q doesn't match an enemy.", t)
+		return nil, errTargetNotFound("an enemy", t, suggest(t, names))
 	case len(found) > 1:
-		return nil, fmt.Errorf("%q matches more than one tribe.", t)
-	case found[0] > Caddo && found[0] != SpanishTribe:
-		return nil, fmt.Errorf("%q doesn't match an enemy.")
+		var matches []string
+		for _, i := range found {
+			matches = append(matches, names[i])
+		}
+		return nil, errAmbiguousTarget("tribe", t, matches)
+	}
+	return tribeNameLookup[names[found[0]]], nil
+}
+
+// matchesPrefix reports whether name begins with prefix, ignoring case.
+// It's how a player's abbreviations are matched to names.
+func matchesPrefix(name, prefix string) bool {
+	return strings.HasPrefix(strings.ToLower(name), strings.ToLower(prefix))
+}
+
+// targetNames lists the names a player can give as a target of the given type.
+func (g *Game) targetNames(tt TargetType) []string {
+	var names []string
+	switch tt {
+	case WarpathTarget, EnemyTarget:
+		for _, t := range tribes {
+			names = append(names, t.String())
+		}
+		if tt == EnemyTarget {
+			names = append(names, SpanishTribe.String())
+		}
+	case LandTarget:
+		for _, l := range g.Board.Lands {
+			names = append(names, l.Name)
+		}
+	}
+	return names
+}
+
+// Complete returns the ways a partly typed command could be completed,
+// each as a whole command.  Actions and targets are matched just as they are
+// when the command is entered, and the action is kept as the player typed
+// it, whether by its code or its name.
+func (g *Game) Complete(line string) []string {
+	var result []string
+	fields := strings.Fields(line)
+	if len(fields) == 0 || (len(fields) == 1 && !strings.HasSuffix(line, " ")) {
+		prefix := ""
+		if len(fields) == 1 {
+			prefix = fields[0]
+		}
+		for _, as := range actions {
+			switch {
+			case matchesPrefix(as.Name, prefix):
+				result = append(result, as.Name)
+			case matchesPrefix(as.Abbr, prefix):
+				result = append(result, as.Abbr)
+			}
+		}
+		return result
+	}
+	as, n, err := findActionSpec(fields)
+	if err != nil {
+		return nil
+	}
+	action := strings.Join(fields[:n], " ")
+	prefix := strings.Join(fields[n:], " ")
+	for _, name := range g.targetNames(as.Target) {
+		if matchName(name, prefix) != noMatch {
+			result = append(result, action+" "+name)
+		}
+	}
+	return result
+}
+
+// LegalInputs lists the inputs the game would currently accept without error,
+// other than quitting.  At the action prompt that's every action that can be
+// performed, with each target it can be performed on; at a yes or no
+// question it's "y" and "n".
+func (g *Game) LegalInputs() []Input {
+	var result []Input
+	switch g.State.(type) {
+	case stateVerifyQuitGame:
+		return []Input{"y", "n"}
+	case stateProcessAction:
+	default:
+		return nil
+	}
+	for _, as := range actions {
+		ca, ok := as.Type.(checkedAction)
+		if !ok {
+			continue
+		}
+		if as.Target == NoTarget {
+			if ca.check(g, nil) == nil {
+				result = append(result, Input(as.Name))
+			}
+			continue
+		}
+		for _, n := range g.targetNames(as.Target) {
+			target, err := findFunction[as.Target](strings.ToLower(n), g)
+			if err != nil {
+				continue
+			}
+			if _, err := g.checkCost(&as, target); err != nil {
+				continue
+			}
+			if ca.check(g, target) == nil {
+				result = append(result, Input(as.Name+" "+n))
+			}
+		}
 	}
-	return found[0], nil
+	return result
+}
+
+// Actions returns the specifications of all the actions a player can take.
+func Actions() []ActionSpec {
+	return append([]ActionSpec(nil), actions...)
 }
 
 // executedAction is called whenever an action is legally performed (even if it didn't)
@@ -203,21 +400,21 @@ func (g *Game) executedAction() {
 // findPeacePipeLands finds the Land on a tribe's warpath that currently contains the
 // peace pipe, if any, and the next land to which the peace pipe can be moved,
 // if any.
-func (g *Game) findPeacePipeLands(t Tribe) (Land, Land) {
+func (b *Board) findPeacePipeLands(t Tribe) (Land, Land) {
 	var oldLand, newLand Land
 	for i := 1; i < 6; i++ {
 		idx := toLandIndex(t, i)
-		if g.Board.PeacePipes[idx] {
-			oldLand = g.Board.Lands[idx]
+		if b.PeacePipes[idx] {
+			oldLand = b.Lands[idx]
 			break
 		}
 	}
 
 	if (oldLand == Land{}) {
-		newLand = g.Board.Lands[toLandIndex(t, 1)]
+		newLand = b.Lands[toLandIndex(t, 1)]
 	} else {
 		if oldLand.Space < 5 {
-			newLand = g.Board.Lands[oldLand.Index+1]
+			newLand = b.Lands[oldLand.Index+1]
 		}
 	}
 
@@ -243,8 +440,7 @@ func (g *Game) advancePeacePipe(oldLand, newLand Land) {
 		return
 	}
 	newLand = g.Board.Lands[newLand.Index+1]
-	if !newLand.IsWilderness {
-		g.drawChiefdomCounter(newLand)
+	if !newLand.IsWilderness && g.drawChiefdomCounter(newLand) {
 		g.logEvent("Explored new chiefdom (%s) in %s.", g.Board.Chiefdoms[newLand.Index], newLand)
 	}
 }
@@ -262,36 +458,53 @@ func (a PeacePipeAction) perform(g *Game, t Tribe, mutate bool) (state, error) {
 	var err error
 
 	if g.Board.CurrentEra != Hopewell {
-		err = fmt.Errorf("This action is only allowed during the Hopewell era.")
+		err = errWrongEra(Hopewell)
 		return stateGetNextAction{}, err
 	}
 
-	oldLand, newLand := g.findPeacePipeLands(t)
+	oldLand, newLand := g.Board.findPeacePipeLands(t)
+	_, costErr := g.checkCost(specFor(a), t)
 
 	switch {
-	case g.Board.ActionPoints < 1:
-		err = errors.New("Not enough APs remaining; 1 required.")
+	case costErr != nil:
+		err = costErr
 	case newLand == Land{}:
-		err = fmt.Errorf("Peace Pipe on %s cannot be advanced.", oldLand)
+		err = errEndOfWarpath(t, oldLand)
 	case newLand.IsWilderness:
 		if mutate {
 				g.advancePeacePipe(oldLand, newLand)
 				g.executedAction()
 		}
+	case g.Board.Chiefdoms[newLand.Index] == nil:
+		// the land's chiefdom was lost, so the peace pipe explores it
+		// again; it goes on only if the cup is empty and nothing is found
+		if mutate {
+			if g.drawChiefdomCounter(newLand) {
+				g.logEvent("Explored new chiefdom (%s) in %s.", g.Board.Chiefdoms[newLand.Index], newLand)
+			} else {
+				g.advancePeacePipe(oldLand, newLand)
+			}
+			g.executedAction()
+		}
 	case g.Board.Chiefdoms[newLand.Index].IsMounded:
 		if mutate {
 				g.advancePeacePipe(oldLand, newLand)
 				g.executedAction()
 		}
 	default:
-		err = fmt.Errorf("Cannot advance Peace Pipe; chiefdom in %s must be incorporated first.", newLand)
+		err = errBlockedPeacePipe(t, newLand)
 	}
 	return stateGetNextAction{}, err
 }
 
-func (a PeacePipeAction) isEnabledOnWarpath(g *Game, t Tribe) bool {
+func (a PeacePipeAction) check(g *Game, target interface{}) error {
+	_, err := a.perform(g, target.(Tribe), false)
+	return err
+}
+
+func (a PeacePipeAction) checkWarpath(g *Game, t Tribe) error {
 	_, err := a.perform(g, t, false)
-	return err == nil
+	return err
 }
 
 func (a IncorporateAction) handle(g *Game) state {
@@ -303,46 +516,43 @@ func (a IncorporateAction) handle(g *Game) state {
 	return s
 }
 
-func (IncorporateAction) perform(g *Game, t Tribe, mutate bool) (state, error) {
+func (a IncorporateAction) perform(g *Game, t Tribe, mutate bool) (state, error) {
 	var err error
 
 	if g.Board.CurrentEra != Hopewell {
-		err = fmt.Errorf("This action is only allowed during the Hopewell era.")
+		err = errWrongEra(Hopewell)
 		return stateGetNextAction{}, err
 	}
 
-	oldLand, newLand := g.findPeacePipeLands(t)
+	oldLand, newLand := g.Board.findPeacePipeLands(t)
+	_, costErr := g.checkCost(specFor(a), t)
 
 	switch {
-	case g.Board.ActionPoints < 1:
-		err = errors.New("Not enough APs remaining; 1 required.")
+	case costErr != nil:
+		err = costErr
 	case newLand == Land{}:
-		err = fmt.Errorf("Cannot advance Peace Pipe beyond %s.", oldLand)
+		err = errEndOfWarpath(t, oldLand)
 	case newLand.IsWilderness:
-		err = fmt.Errorf("%s cannot contain a chiefdom.", newLand)
+		err = errWilderness(newLand)
 	case g.Board.Chiefdoms[newLand.Index] == nil:
-		err = fmt.Errorf("%s does not contain a chiefdom.", newLand)
+		err = errNoChiefdom(newLand)
 	case !mutate:
 		break
 	default:
 		var r int
 		if (oldLand != Land{}) {
-			r1, r2 := die(), die()
+			r1, r2 := g.die(), g.die()
 			r = r2
 			if r1 > r2 {
 				r = r1
 			}
 			g.logEvent("Busk roll on %s warpath: %d and %d, choosing %d.", t, r1, r2, r)
 		} else {
-			r = die()
+			r = g.die()
 			g.logEvent("Diplomacy roll on %s warpath : %d.", t, r)
 		}
 		oldChiefdom := g.Board.Chiefdoms[newLand.Index]
-		v := oldChiefdom.getValue()
-		if g.Board.WarpathStatus.Warpath == t {
-			g.logEvent("%s status modifies chiefdom's value of %d.", g.Board.WarpathStatus, v)
-			v += g.Board.WarpathStatus.Modifier
-		}
+		v := g.modified(t, "the chiefdom's value", oldChiefdom.getValue())
 		if r > v {
 			g.logEvent("%d exceeded value of %d; incorporation succeeded.", r, v)
 			oldChiefdom.IsControlled = true
@@ -360,9 +570,14 @@ func (IncorporateAction) perform(g *Game, t Tribe, mutate bool) (state, error) {
 	return stateGetNextAction{}, err
 }
 
-func (a IncorporateAction) isEnabledOnWarpath(g *Game, t Tribe) bool {
+func (a IncorporateAction) check(g *Game, target interface{}) error {
+	_, err := a.perform(g, target.(Tribe), false)
+	return err
+}
+
+func (a IncorporateAction) checkWarpath(g *Game, t Tribe) error {
 	_, err := a.perform(g, t, false)
-	return err == nil
+	return err
 }
 
 func (a BuildAction) handle(g *Game) state {
@@ -374,27 +589,36 @@ func (a BuildAction) handle(g *Game) state {
 	return s
 }
 
-func (a BuildAction) isEnabledForChiefdom(g *Game, c Chiefdom) bool {
+func (a BuildAction) check(g *Game, target interface{}) error {
+	_, err := a.perform(g, target.(Land), false)
+	return err
+}
+
+func (a BuildAction) checkChiefdom(g *Game, c Chiefdom) error {
 	l := g.Board.Lands[c.LandIndex]
 	_, err := a.perform(g, l, false)
-	return err == nil
+	return err
 }
 
-func (BuildAction) perform(g *Game, l Land, mutate bool) (state, error) {
+func (a BuildAction) perform(g *Game, l Land, mutate bool) (state, error) {
 	var err error
 	c := g.Board.Chiefdoms[l.Index]
+	_, costErr := g.checkCost(specFor(a), l)
 
 	switch {
+	case c == nil:
+		err = errNoChiefdom(l)
 	case c.IsMounded:
-		err = fmt.Errorf("%s is already mounded.")
+		err = errAlreadyMounded(l)
 	case !c.IsControlled:
-		err = fmt.Errorf("You do not control %s yet.")
-	case c.getValue() > g.Board.ActionPoints:
-		err = fmt.Errorf("Not enough APs available; %d required.", c.getValue())
+		err = errNotControlled(l)
+	case g.Board.isOccupied(l.Index):
+		err = errOccupied(l)
+	case costErr != nil:
+		err = costErr
 	case !mutate:
 		break
 	default:
-		g.Action.ActualCost = c.getValue()
 		c.IsMounded = true
 		g.logEvent("Built mound for chiefdom in %s.", l.Name)
 		g.executedAction()
@@ -411,8 +635,8 @@ func (AttackAction) handle(g *Game) state {
 	return stateGetNextAction{}
 }
 
-func (AttackAction) isEnabledOnWarpath(g *Game, t Tribe) bool {
-	return false
+func (a AttackAction) checkWarpath(*Game, Tribe) error {
+	return errNotImplemented(specFor(a))
 }
 
 func (RepairAction) handle(g *Game) state {
@@ -423,11 +647,20 @@ func (PowwowAction) handle(g *Game) state {
 	return stateGetNextAction{}
 }
 
-func (PowwowAction) isEnabledOnWarpath(g *Game, t Tribe) bool {
-	return false
+func (a PowwowAction) checkWarpath(*Game, Tribe) error {
+	return errNotImplemented(specFor(a))
 }
 
 
+func (PassAction) handle(g *Game) state {
+	g.logEvent("Passed with %d APs unspent.", g.Board.ActionPoints)
+	return stateEndOfTurnPhase{}
+}
+
+func (PassAction) check(*Game, interface{}) error {
+	return nil
+}
+
 func (QuitAction) handle(g *Game) state {
 	g.respond("Do you really want to quit (Y/N)?", nil)
 	return stateVerifyQuitGame(0)
@@ -436,7 +669,8 @@ func (QuitAction) handle(g *Game) state {
 type stateVerifyQuitGame int
 
 func (stateVerifyQuitGame) handle(g *Game) state {
-	if strings.ToLower(string(g.Request.Input)) == "y" {
+	if strings.ToLower(strings.TrimSpace(string(g.Request.Input))) == "y" {
+		g.EndCause = "The player quit."
 		return stateEndOfGame{}
 	}
 	return stateGetNextAction{}
@@ -0,0 +1,126 @@
+package mb
+
+import (
+	"reflect"
+	"testing"
+)
+
+// emptiedLandGame returns a game in the Action Phase with the Natchez peace
+// pipe in space 1 and no chiefdom in space 2, as if it had been lost.
+func emptiedLandGame(t *testing.T) (*Game, Land) {
+	t.Helper()
+	g := startedTestGame(t)
+	for i := range g.Board.PeacePipes {
+		g.Board.PeacePipes[i] = false
+	}
+	first := g.Board.Lands[toLandIndex(Natchez, 1)]
+	g.Board.Chiefdoms[first.Index].IsControlled = true
+	g.Board.PeacePipes[first.Index] = true
+	next := g.Board.Lands[toLandIndex(Natchez, 2)]
+	g.removeChiefdom(next)
+	g.Board.ActionPoints = 5
+	return g, next
+}
+
+func TestPeacePipeExploresEmptiedLand(t *testing.T) {
+	g, next := emptiedLandGame(t)
+	cup := len(g.Cup)
+	g.HandleRequest(Request{Input: "ppa Natchez"})
+	if g.Response.Error != nil {
+		t.Fatal(g.Response.Error)
+	}
+	if g.Board.Chiefdoms[next.Index] == nil || len(g.Cup) != cup-1 {
+		t.Errorf("no chiefdom was drawn for %s", next.Name)
+	}
+	if g.Board.PeacePipes[next.Index] {
+		t.Errorf("the peace pipe went on to %s without incorporating its new chiefdom", next.Name)
+	}
+	if g.Board.ActionPoints != 4 {
+		t.Errorf("%d APs left; exploring should cost 1", g.Board.ActionPoints)
+	}
+	g.HandleRequest(Request{Input: "ppa Natchez"})
+	if e, ok := g.Response.Error.(*RuleError); !ok || e.Code != ErrBlockedPeacePipe {
+		t.Errorf("advancing again returned %v; want a %s error", g.Response.Error, ErrBlockedPeacePipe)
+	}
+}
+
+func TestPeacePipePassesEmptiedLandWithEmptyCup(t *testing.T) {
+	g, next := emptiedLandGame(t)
+	g.Cup = nil
+	g.HandleRequest(Request{Input: "ppa Natchez"})
+	if g.Response.Error != nil {
+		t.Fatal(g.Response.Error)
+	}
+	if !g.Board.PeacePipes[next.Index] || g.Board.Chiefdoms[next.Index] != nil {
+		t.Errorf("with nothing to draw, the peace pipe should pass through %s", next.Name)
+	}
+}
+
+func TestComplete(t *testing.T) {
+	tests := []struct {
+		line string
+		want []string
+	}{
+		{"in", []string{"inc"}},
+		{"Incor", []string{"Incorporate"}},
+		{"pea", []string{"Peace Pipe"}},
+		{"inc ch", []string{"inc Cherokee"}},
+		{"incorporate ch", []string{"incorporate Cherokee"}},
+		{"build chu", []string{"build Chucalissa"}},
+		{"Peace Pipe ca", []string{"Peace Pipe Caddo"}},
+		{"pow ho", []string{"pow HoChunk"}},
+		{"xyz ", nil},
+	}
+	g := startedTestGame(t)
+	for _, tt := range tests {
+		if got := g.Complete(tt.line); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Complete(%q) = %q; want %q", tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestActionErrorCodes(t *testing.T) {
+	kincaid := toLandIndex(Natchez, 1)
+	control := func(g *Game) { g.Board.Chiefdoms[kincaid].IsControlled = true }
+	tests := []struct {
+		in    Input
+		setup func(g *Game)
+		want  ErrorCode
+	}{
+		{"mnd Kincaid", func(g *Game) { control(g); g.Board.ActionPoints = 1 }, ErrInsufficientAP},
+		{"ppa Natchez", func(g *Game) { g.Board.CurrentEra = Mississippian }, ErrWrongEra},
+		{"inc Natchez", func(g *Game) { g.Board.CurrentEra = Mississippian }, ErrWrongEra},
+		{"mnd Chucalissa", nil, ErrNoChiefdom},
+		{"inc Natchez", func(g *Game) {
+			control(g)
+			g.Board.PeacePipes[kincaid] = true
+			g.removeChiefdom(g.Board.Lands[toLandIndex(Natchez, 2)])
+		}, ErrNoChiefdom},
+		{"inc Caddo", func(g *Game) {
+			toltec := toLandIndex(Caddo, 1)
+			g.Board.Chiefdoms[toltec].IsControlled = true
+			g.Board.PeacePipes[toltec] = true
+		}, ErrWilderness},
+		{"mnd Kincaid", nil, ErrNotControlled},
+		{"mnd Kincaid", func(g *Game) { control(g); g.Board.Chiefdoms[kincaid].IsMounded = true }, ErrAlreadyMounded},
+		{"ppa Natchez", nil, ErrBlockedPeacePipe},
+		{"inc Natchez", func(g *Game) { g.Board.PeacePipes[toLandIndex(Natchez, 5)] = true }, ErrEndOfWarpath},
+		{"ppa Natchez", func(g *Game) { g.Board.PeacePipes[toLandIndex(Natchez, 5)] = true }, ErrEndOfWarpath},
+		{"mnd Kincaid", func(g *Game) { control(g); g.Board.findHostile(Natchez).LandIndex = kincaid }, ErrOccupied},
+	}
+	for _, tt := range tests {
+		g := startedTestGame(t)
+		g.Board.ActionPoints = 10
+		if tt.setup != nil {
+			tt.setup(g)
+		}
+		ap := g.Board.ActionPoints
+		g.HandleRequest(Request{Input: tt.in})
+		if e, ok := g.Response.Error.(*RuleError); !ok || e.Code != tt.want {
+			t.Errorf("%q returned %v; want a %s error", tt.in, g.Response.Error, tt.want)
+		}
+		if g.Board.ActionPoints != ap {
+			t.Errorf("%q: %d APs left; want the %d there were", tt.in, g.Board.ActionPoints, ap)
+		}
+	}
+}
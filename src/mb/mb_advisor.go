@@ -0,0 +1,119 @@
+package mb
+
+import (
+	"errors"
+	"math/rand"
+	"sort"
+)
+
+// DefaultRollouts is how many games Advise plays out for each option when
+// it isn't told otherwise.
+const DefaultRollouts = 20
+
+// maxRolloutRequests stops a rollout that doesn't end.
+const maxRolloutRequests = 5000
+
+// Advice is the advisor's estimate for one legal action.
+type Advice struct {
+	Input         Input
+	ExpectedScore float64 // mean final score of the rollouts that finished
+	WinRate       float64 // fraction of the rollouts that finished that were won
+	Rollouts      int
+	Failed        int // rollouts that failed part way, left out of the above
+}
+
+// Advise ranks the actions the player can take now by their expected final
+// score, best first.  For each legal action it plays the action on copies of
+// the game, then plays each copy to the end with HeuristicPlayer.  The
+// copies reshuffle what the player can't see, the order of the History Deck
+// and the cup, so the advice doesn't depend on hidden information.  A
+// rollout that fails part way says nothing about the final score, so it's
+// counted as failed rather than averaged in.
+//
+// Advice is only available during the Action Phase.
+func (g *Game) Advise(rollouts int, seed int64) ([]Advice, error) {
+	if _, ok := g.State.(stateProcessAction); !ok || g.Response == nil {
+		return nil, errors.New("Advice is only available when choosing an action.")
+	}
+	if rollouts < 1 {
+		rollouts = DefaultRollouts
+	}
+	r := rand.New(rand.NewSource(seed))
+	var advice []Advice
+	for _, in := range g.LegalInputs() {
+		a := Advice{Input: in, Rollouts: rollouts}
+		for i := 0; i < rollouts; i++ {
+			c := g.simulationCopy()
+			c.reshuffleHidden(r)
+			res := c.rollout(in)
+			if c.Failed() != nil {
+				a.Failed++
+				continue
+			}
+			a.ExpectedScore += float64(res.Score)
+			if res.Won {
+				a.WinRate++
+			}
+		}
+		if finished := rollouts - a.Failed; finished > 0 {
+			a.ExpectedScore /= float64(finished)
+			a.WinRate /= float64(finished)
+		}
+		advice = append(advice, a)
+	}
+	sort.SliceStable(advice, func(i, j int) bool {
+		return advice[i].ExpectedScore > advice[j].ExpectedScore
+	})
+	return advice, nil
+}
+
+// reshuffleHidden reshuffles the parts of the game the player can't see and
+// reseeds its dice; see shuffledDeck for how the History Deck is shuffled.
+func (g *Game) reshuffleHidden(r *rand.Rand) {
+	g.seed(r.Int63())
+	shuffleCup(g.Cup, r)
+	g.HistoryDeck = g.shuffledDeck(r)
+}
+
+// shuffledDeck returns a copy of the History Deck shuffled as far as the
+// player can tell.  Each remaining section of the deck is shuffled, and
+// cards of the same era are swapped between sections, since only the number
+// of each era's cards in a section is known.  The Spanish cards stay put:
+// each is known to be in its own final stack.
+func (g *Game) shuffledDeck(r *rand.Rand) Pile {
+	deck := append(Pile(nil), g.HistoryDeck...)
+	for _, sr := range g.sectionRanges() {
+		shufflePile(deck[sr[0]:sr[1]], r)
+	}
+	positions := make(map[Era][]int)
+	for i, c := range deck {
+		if c.Era != Spanish {
+			positions[c.Era] = append(positions[c.Era], i)
+		}
+	}
+	for _, ps := range positions {
+		cards := make(Pile, len(ps))
+		for i, p := range ps {
+			cards[i] = deck[p]
+		}
+		shufflePile(cards, r)
+		for i, p := range ps {
+			deck[p] = cards[i]
+		}
+	}
+	return deck
+}
+
+// rollout makes the given request, then plays the game to the end with
+// HeuristicPlayer and returns the result.  It stops if the game fails.
+func (g *Game) rollout(in Input) Result {
+	g.HandleRequest(Request{Input: in})
+	for n := 0; n < maxRolloutRequests && !g.IsOver() && g.Failed() == nil; n++ {
+		q, ok := g.NextRequest(HeuristicPlayer{})
+		if !ok {
+			break
+		}
+		g.HandleRequest(q)
+	}
+	return g.Result()
+}
@@ -0,0 +1,43 @@
+package mb
+
+import "testing"
+
+// withoutEvents removes the cards with special events from the game's deck,
+// so that games can be played to the end.
+func withoutEvents(g *Game) {
+	var deck Pile
+	for _, c := range g.HistoryDeck {
+		if len(c.effects(SpecialEventEffect)) == 0 {
+			deck = append(deck, c)
+		}
+	}
+	g.HistoryDeck = deck
+}
+
+func TestAdviseLeavesOutFailedRollouts(t *testing.T) {
+	// every rollout reaches the Avaricia event, which fails as it isn't
+	// part of the game yet
+	g := startedTestGame(t)
+	advice, err := g.Advise(2, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, a := range advice {
+		if a.Failed != a.Rollouts || a.ExpectedScore != 0 || a.WinRate != 0 {
+			t.Errorf("%s: %d of %d rollouts failed, with an expected score of %.1f and win rate of %.2f; want all failed and nothing averaged",
+				a.Input, a.Failed, a.Rollouts, a.ExpectedScore, a.WinRate)
+		}
+	}
+
+	g = startedTestGame(t)
+	withoutEvents(g)
+	advice, err = g.Advise(2, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, a := range advice {
+		if a.Failed != 0 || a.ExpectedScore == 0 {
+			t.Errorf("%s: %d rollouts failed, with an expected score of %.1f; want none failed", a.Input, a.Failed, a.ExpectedScore)
+		}
+	}
+}
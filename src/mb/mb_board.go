@@ -1,5 +1,7 @@
 package mb
 
+import "fmt"
+
 var boardData = `Tribe,Name,IsWilderness
 HoChunk,Ho-Chunk Homeland,
 HoChunk,Red Wing,TRUE
@@ -51,16 +53,80 @@ func fromLandIndex(i int) (Tribe, int) {
 	return t, n
 }
 
+// findHostile returns the given tribe's hostile marker, or the Spanish
+// marker for SpanishTribe.  It returns nil if the marker isn't on the board.
 func (b Board) findHostile(t Tribe) *HostileMarker {
-	for i := 0; i < len(b.Hostiles); i++ {
-		h := b.Hostiles[i]
-		if h != nil && t == Tribe(i/6) {
+	for _, h := range b.Hostiles {
+		if h.Tribe == t {
 			return h
 		}
 	}
 	return nil
 }
 
+// hostileSpace returns the space a tribe's hostile marker is on, or 0 if
+// the marker isn't on the board.
+func (b Board) hostileSpace(t Tribe) int {
+	h := b.findHostile(t)
+	if h == nil {
+		return 0
+	}
+	_, n := fromLandIndex(h.LandIndex)
+	return n
+}
+
+// hostilesAt returns the hostile markers on the land with the given index.
+func (b Board) hostilesAt(i int) []*HostileMarker {
+	var hs []*HostileMarker
+	for _, h := range b.Hostiles {
+		if h.LandIndex == i {
+			hs = append(hs, h)
+		}
+	}
+	return hs
+}
+
+// checkHostiles checks that there's one hostile marker for each tribe, on
+// its own warpath, and at most one Spanish marker, on a land.
+func (b Board) checkHostiles() error {
+	seen := make(map[Tribe]bool)
+	for _, h := range b.Hostiles {
+		switch {
+		case h == nil:
+			return fmt.Errorf("A hostile marker is missing.")
+		case h.Tribe > SpanishTribe:
+			return fmt.Errorf("There is a hostile marker for %s.", h.Tribe)
+		case seen[h.Tribe]:
+			return fmt.Errorf("There is more than one %s hostile marker.", h.Tribe)
+		case h.LandIndex < 0 || h.LandIndex >= LandCount:
+			return fmt.Errorf("The %s hostile marker is off the board at %d.", h.Tribe, h.LandIndex)
+		}
+		if t, _ := fromLandIndex(h.LandIndex); !h.IsSpanish() && t != h.Tribe {
+			return fmt.Errorf("The %s hostile marker is on the %s warpath.", h.Tribe, t)
+		}
+		seen[h.Tribe] = true
+	}
+	for _, t := range tribes {
+		if !seen[t] {
+			return fmt.Errorf("The %s hostile marker is missing.", t)
+		}
+	}
+	return nil
+}
+
+// isOccupied reports whether a hostile army stands on the land with the
+// given index.
+func (b Board) isOccupied(i int) bool {
+	return len(b.hostilesAt(i)) > 0
+}
+
+// controls reports whether the player controls a chiefdom and has the use of
+// it.  A chiefdom occupied by a hostile army earns nothing and scores
+// nothing until the army moves on.
+func (b Board) controls(c *Chiefdom) bool {
+	return c != nil && c.IsControlled && !b.isOccupied(c.LandIndex)
+}
+
 func (b Board) findLand(t Tribe, n int) Land {
 	return b.Lands[toLandIndex(t, n)]
 }
@@ -69,6 +135,30 @@ func (b Board) findChiefdom(t Tribe, n int) *Chiefdom {
 	return b.Chiefdoms[toLandIndex(t, n)]
 }
 
+// controlledTradeGoods counts the trade goods earned this turn: one for each
+// controlled chiefdom that isn't occupied.
+func (b Board) controlledTradeGoods() int {
+	n := 0
+	for _, c := range b.Chiefdoms {
+		if b.controls(c) {
+			n++
+		}
+	}
+	return n
+}
+
+// controlledLandsWithGood counts the controlled chiefdoms that produce the
+// given trade good, leaving out occupied ones.
+func (b Board) controlledLandsWithGood(t TradeGood) int {
+	n := 0
+	for _, c := range b.Chiefdoms {
+		if b.controls(c) && c.Counter.Good == t {
+			n++
+		}
+	}
+	return n
+}
+
 // moveHostile moves a hostile marker one space towards or away from Cahokia.
 // It is assumed that the destination space is legal, e.g. this will move
 // a hostile into Cahokia whether or not the palisade is intact.
@@ -88,9 +178,7 @@ func (b *Board) moveHostile(h *HostileMarker, dir int) {
 	if dir < 0 && i <= 1 {
 		return
 	}
-	b.Hostiles[h.LandIndex] = nil
 	h.LandIndex += dir
-	b.Hostiles[h.LandIndex] = h
 }
 
 // palisade is a helper function to get the current Palisade.
@@ -98,55 +186,60 @@ func (b *Board) palisade() Palisade {
 	return b.Palisades[b.PalisadeIndex]
 }
 
-// makeBoard makes a new game board.
-func makeBoard() Board {
-	board := Board{
-		Lands:      make([]Land, LandCount),
-		Chiefdoms:  make([]*Chiefdom, LandCount),
-		Hostiles:   make([]*HostileMarker, LandCount),
-		PeacePipes: make([]bool, LandCount),
-	}
-	for i, r := range parse(boardData) {
-		if i == 0 {
-			// skip the header row
+var boardColumns = []string{"Tribe", "Name", "IsWilderness"}
+
+// makeLands makes the lands from the board data.  Each warpath's lands are
+// listed in order from the tribal homeland in towards Cahokia.
+func makeLands(t *table) ([]Land, error) {
+	lands := make([]Land, LandCount)
+	spaces := make(map[Tribe]int)
+	for i := range t.rows {
+		tribe := t.tribe(i, colTribe, None)
+		if tribe > Caddo {
+			if tribe != None {
+				t.fail(i, colTribe, "%s does not have a warpath", tribe)
+			}
+			continue
+		}
+		n := spaces[tribe]
+		spaces[tribe]++
+		if n >= 6 {
+			t.fail(i, colTribe, "the %s warpath already has 6 lands", tribe)
 			continue
 		}
 		l := Land{
-			Warpath:      tribeNameLookup[r[colTribe]],
-			Name:         r[colName],
-			Space:        6 - ((i - 1) % 6),
-			IsWilderness: r[colIsWilderness] == "TRUE",
+			Warpath:      tribe,
+			Name:         t.required(i, colName),
+			Space:        6 - n,
+			IsWilderness: t.bool(i, colIsWilderness),
 		}
 		l.Index = toLandIndex(l.Warpath, l.Space)
-		board.Lands[l.Index] = l
+		lands[l.Index] = l
+	}
+	return lands, t.err()
+}
+
+// makeBoard makes a new game board.
+func makeBoard(o GameOptions, lands []Land) Board {
+	board := Board{
+		Lands:      make([]Land, LandCount),
+		Chiefdoms:  make([]*Chiefdom, LandCount),
+		PeacePipes: make([]bool, LandCount),
 	}
+	copy(board.Lands, lands)
 
 	// position the hostiles
-	vals := []int{4, 3, 2, 2, 3}
-	for t := HoChunk; t <= Caddo; t++ {
-		i := toLandIndex(t, 6)
-		board.Hostiles[i] = &HostileMarker{
-			LandIndex:   i,
-			BattleValue: vals[int(t)],
-		}
+	for _, t := range tribes {
+		board.Hostiles = append(board.Hostiles, &HostileMarker{
+			Tribe:       t,
+			LandIndex:   toLandIndex(t, 6),
+			BattleValue: o.HostileBattleValues[int(t)],
+		})
 	}
 
 	// initialize the palisade
-	pv := []struct {
-		label string
-		value int
-	}{
-		{"4F", 4},
-		{"4E", 4},
-		{"4D", 4},
-		{"3C", 3},
-		{"3B", 3},
-		{"2A", 2},
-	}
-	board.Palisades = make([]Palisade, 6)
-	for i, p := range pv {
-		board.Palisades[i] = Palisade{Label: p.label, Value: p.value}
-	}
+	board.Palisades = make([]Palisade, len(o.Palisades))
+	copy(board.Palisades, o.Palisades)
 
 	return board
 }
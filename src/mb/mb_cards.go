@@ -2,75 +2,67 @@ package mb
 
 import (
 	"fmt"
-	"strconv"
-	"strings"
+	"math/rand"
+	"sort"
 )
 
-var historyCardData = `Number,Title,Era,ActionPoints,IsWhite,ResourceBonus,Revolt,Modifier,IsAscendant,AdvancingArmies,IsAvaricia,IsSpanish
-1,Poverty Point,Hopewell,1,TRUE,,Caddo,Caddo,,,,
-2,Bynum,Hopewell,3,TRUE,,Natchez,Natchez,,,,
-3,Marksville,Hopewell,4,TRUE,,Caddo,Caddo,,,,
-4,Portsmouth,Hopewell,3,TRUE,,Shawnee,Shawnee,,,,
-5,Pinson,Hopewell,2,TRUE,,Cherokee,Cherokee,,,,
-6,Newark,Hopewell,4,TRUE,,Natchez,Shawnee,,,,
-7,Lizard Mound,Hopewell,3,TRUE,,HoChunk,HoChunk,TRUE,,,
-8,Toltec,Hopewell,2,TRUE,,Caddo,Caddo,,,,
-9,Lake Koshkonong,Hopewell,4,TRUE,,HoChunk,HoChunk,TRUE,,,
-10,Harlan,Hopewell,4,TRUE,,Shawnee,Caddo,,,,
-11,Dickson,Hopewell,2,TRUE,,HoChunk,HoChunk,TRUE,,,
-12,Spiro,Hopewell,3,TRUE,,Cherokee,Caddo,,,,
-13,Aztalan,Mississippian,4,TRUE,,,HoChunk,,Cherokee,,
-14,Ocmulgee,Mississippian,3,TRUE,,,Cherokee,TRUE,"Caddo,Cherokee",,
-15,Fort Ancient,Mississippian,2,TRUE,,,Shawnee,TRUE,Shawnee,,
-16,Red Wing,Mississippian,3,TRUE,,,HoChunk,,"Cherokee,Natchez",,
-17,Anhaica,Mississippian,4,TRUE,,HoChunk,Cherokee,,Caddo,,
-18,Etowah,Mississippian,2,TRUE,,Natchez,Cherokee,TRUE,Cherokee,,
-19,Moundville,Mississippian,4,TRUE,,Cherokee,Natchez,TRUE,"HoChunk,Natchez",,
-20,Chucalissa,Mississippian,2,TRUE,,,Natchez,,Caddo,,
-21,Angel,Mississippian,4,TRUE,,,Shawnee,,"HoChunk,Cherokee",,
-22,Kincaid,Mississippian,1,TRUE,,,Natchez,TRUE,"Natchez,Shawnee",,
-23,Serpent Mound,Mississippian,3,TRUE,,Caddo,Shawnee,TRUE,"Natchez,Shawnee",,
-24,Bottle Creek,Mississippian,3,TRUE,,,Natchez,,"Caddo,Cherokee",,
-25,Coosa,Spanish,4,TRUE,,,Cherokee,TRUE,"Cherokee,Shawnee,Natchez",TRUE,
-26,The Spanish,Spanish,0,TRUE,,,,,"HoChunk,Shawnee,Spanish",,TRUE
-27,Chalcedony & Obsidian,Generic,4,,"Chalcedony,Obsidian",,,,"Caddo,Caddo,Natchez",,
-28,Pipestone,Generic,4,,Pipestone,,,,"HoChunk,Shawnee",,
-29,Mica & Seashells,Generic,5,,"Mica,Seashells",,,,"Natchez,Cherokee,Cherokee",,
-30,Hides & Feathers,Generic,6,,"Hides,Feathers",,,,"Cherokee,Caddo",,
-31,Chert,Generic,5,,Chert,,,,"Natchez,Shawnee,Cherokee",,
-32,Copper,Generic,4,,Copper,,,,"HoChunk,HoChunk,Shawnee,Cherokee",,
-33,Tobacco,Generic,3,,,,Cherokee,,"Cherokee,Natchez",,
-34,Sunflowers,Generic,4,,,,Caddo,,"Caddo,Natchez",,
-35,The Three Sisters,Generic,2,,,,Natchez,,"Natchez,Caddo",,
-36,Mobilian Jargon,Generic,4,,,Cherokee,,,"Natchez,Shawnee",,
-37,The Chunkey Game,Generic,5,,,,,,CaddoOrShawnee,,
-38,Adena Culture,Generic,5,,,,,,"Caddo,Shawnee,Shawnee,Natchez",,
-39,Hopewell Culture,Generic,3,,,,,,"Shawnee,HoChunk",,
-40,Mississippian Culture,Generic,4,,,,,,"Natchez,Cherokee,Cherokee",,
-41,Burial Mounds,Generic,3,,,Natchez,,,"HoChunk,HoChunk,Caddo,Caddo",,
-42,Platform Mounds,Generic,2,,,Caddo,,,"Shawnee,Natchez,Cherokee",,
-43,Effigy Mounds,Generic,2,,,,,,"HoChunk,HoChunk,Shawnee,Shawnee,Caddo",,
-44,Pottery,Generic,3,,,Shawnee,All,TRUE,"Natchez,Cherokee",,
-45,The Buzzard Cult,Generic,7,,,,,,Cherokee,,
-46,Wattle & Daub,Generic,3,,,,All,,"Shawnee,Caddo",,
-47,Oneota Culture,Generic,6,,,,,,"HoChunk,HoChunk,Natchez,Shawnee",,
-48,Human Sacrifice,Generic,6,,,,All,TRUE,"Cherokee,Cherokee,Natchez,Natchez",,
-49,Black Drink,Generic,5,,,,All,,"Cherokee,Caddo",,
-50,Cahokia,Generic,1,,,,All,,Cherokee,,`
+var historyCardData = `Number,Title,Era,Effects
+1,Poverty Point,Hopewell,"GainAP 1 White, SetModifier Caddo Declining, Revolt Caddo"
+2,Bynum,Hopewell,"GainAP 3 White, SetModifier Natchez Declining, Revolt Natchez"
+3,Marksville,Hopewell,"GainAP 4 White, SetModifier Caddo Declining, Revolt Caddo"
+4,Portsmouth,Hopewell,"GainAP 3 White, SetModifier Shawnee Declining, Revolt Shawnee"
+5,Pinson,Hopewell,"GainAP 2 White, SetModifier Cherokee Declining, Revolt Cherokee"
+6,Newark,Hopewell,"GainAP 4 White, SetModifier Shawnee Declining, Revolt Natchez"
+7,Lizard Mound,Hopewell,"GainAP 3 White, SetModifier HoChunk Ascendant, Revolt HoChunk"
+8,Toltec,Hopewell,"GainAP 2 White, SetModifier Caddo Declining, Revolt Caddo"
+9,Lake Koshkonong,Hopewell,"GainAP 4 White, SetModifier HoChunk Ascendant, Revolt HoChunk"
+10,Harlan,Hopewell,"GainAP 4 White, SetModifier Caddo Declining, Revolt Shawnee"
+11,Dickson,Hopewell,"GainAP 2 White, SetModifier HoChunk Ascendant, Revolt HoChunk"
+12,Spiro,Hopewell,"GainAP 3 White, SetModifier Caddo Declining, Revolt Cherokee"
+13,Aztalan,Mississippian,"GainAP 4 White, SetModifier HoChunk Declining, AdvanceArmies Cherokee"
+14,Ocmulgee,Mississippian,"GainAP 3 White, SetModifier Cherokee Ascendant, AdvanceArmies Caddo Cherokee"
+15,Fort Ancient,Mississippian,"GainAP 2 White, SetModifier Shawnee Ascendant, AdvanceArmies Shawnee"
+16,Red Wing,Mississippian,"GainAP 3 White, SetModifier HoChunk Declining, AdvanceArmies Cherokee Natchez"
+17,Anhaica,Mississippian,"GainAP 4 White, SetModifier Cherokee Declining, AdvanceArmies Caddo, Revolt HoChunk"
+18,Etowah,Mississippian,"GainAP 2 White, SetModifier Cherokee Ascendant, AdvanceArmies Cherokee, Revolt Natchez"
+19,Moundville,Mississippian,"GainAP 4 White, SetModifier Natchez Ascendant, AdvanceArmies HoChunk Natchez, Revolt Cherokee"
+20,Chucalissa,Mississippian,"GainAP 2 White, SetModifier Natchez Declining, AdvanceArmies Caddo"
+21,Angel,Mississippian,"GainAP 4 White, SetModifier Shawnee Declining, AdvanceArmies HoChunk Cherokee"
+22,Kincaid,Mississippian,"GainAP 1 White, SetModifier Natchez Ascendant, AdvanceArmies Natchez Shawnee"
+23,Serpent Mound,Mississippian,"GainAP 3 White, SetModifier Shawnee Ascendant, AdvanceArmies Natchez Shawnee, Revolt Caddo"
+24,Bottle Creek,Mississippian,"GainAP 3 White, SetModifier Natchez Declining, AdvanceArmies Caddo Cherokee"
+25,Coosa,Spanish,"SpecialEvent Avaricia, GainAP 4 White, SetModifier Cherokee Ascendant, AdvanceArmies Cherokee Shawnee Natchez"
+26,The Spanish,Spanish,"SpecialEvent Spanish, GainAP 0 White, AdvanceArmies HoChunk Shawnee Spanish"
+27,Chalcedony & Obsidian,Generic,"GainAP 4 Black, ResourceBonus Chalcedony Obsidian, AdvanceArmies Caddo Caddo Natchez"
+28,Pipestone,Generic,"GainAP 4 Black, ResourceBonus Pipestone, AdvanceArmies HoChunk Shawnee"
+29,Mica & Seashells,Generic,"GainAP 5 Black, ResourceBonus Mica Seashells, AdvanceArmies Natchez Cherokee Cherokee"
+30,Hides & Feathers,Generic,"GainAP 6 Black, ResourceBonus Hides Feathers, AdvanceArmies Cherokee Caddo"
+31,Chert,Generic,"GainAP 5 Black, ResourceBonus Chert, AdvanceArmies Natchez Shawnee Cherokee"
+32,Copper,Generic,"GainAP 4 Black, ResourceBonus Copper, AdvanceArmies HoChunk HoChunk Shawnee Cherokee"
+33,Tobacco,Generic,"GainAP 3 Black, SetModifier Cherokee Declining, AdvanceArmies Cherokee Natchez"
+34,Sunflowers,Generic,"GainAP 4 Black, SetModifier Caddo Declining, AdvanceArmies Caddo Natchez"
+35,The Three Sisters,Generic,"GainAP 2 Black, SetModifier Natchez Declining, AdvanceArmies Natchez Caddo"
+36,Mobilian Jargon,Generic,"GainAP 4 Black, AdvanceArmies Natchez Shawnee, Revolt Cherokee"
+37,The Chunkey Game,Generic,"GainAP 5 Black, AdvanceArmies CaddoOrShawnee"
+38,Adena Culture,Generic,"GainAP 5 Black, AdvanceArmies Caddo Shawnee Shawnee Natchez"
+39,Hopewell Culture,Generic,"GainAP 3 Black, AdvanceArmies Shawnee HoChunk"
+40,Mississippian Culture,Generic,"GainAP 4 Black, AdvanceArmies Natchez Cherokee Cherokee"
+41,Burial Mounds,Generic,"GainAP 3 Black, AdvanceArmies HoChunk HoChunk Caddo Caddo, Revolt Natchez"
+42,Platform Mounds,Generic,"GainAP 2 Black, AdvanceArmies Shawnee Natchez Cherokee, Revolt Caddo"
+43,Effigy Mounds,Generic,"GainAP 2 Black, AdvanceArmies HoChunk HoChunk Shawnee Shawnee Caddo"
+44,Pottery,Generic,"GainAP 3 Black, SetModifier All Ascendant, AdvanceArmies Natchez Cherokee, Revolt Shawnee"
+45,The Buzzard Cult,Generic,"GainAP 7 Black, AdvanceArmies Cherokee"
+46,Wattle & Daub,Generic,"GainAP 3 Black, SetModifier All Declining, AdvanceArmies Shawnee Caddo"
+47,Oneota Culture,Generic,"GainAP 6 Black, AdvanceArmies HoChunk HoChunk Natchez Shawnee"
+48,Human Sacrifice,Generic,"GainAP 6 Black, SetModifier All Ascendant, AdvanceArmies Cherokee Cherokee Natchez Natchez"
+49,Black Drink,Generic,"GainAP 5 Black, SetModifier All Declining, AdvanceArmies Cherokee Caddo"
+50,Cahokia,Generic,"GainAP 1 Black, SetModifier All Declining, AdvanceArmies Cherokee"`
 
 const (
 	colNumber = iota
 	colTitle
 	colEra
-	colActionPoints
-	colIsWhite
-	colResourceBonus
-	colRevolt
-	colModifier
-	colIsAscendant
-	colAdvancingArmies
-	colIsAvaricia
-	colIsSpanish
+	colEffects
 )
 
 // drawFromPile draws the next card from a pile.
@@ -83,83 +75,26 @@ func drawFromPile(p Pile) (*HistoryCard, Pile) {
 	return c, p
 }
 
+var historyCardColumns = []string{"Number", "Title", "Era", "Effects"}
+
 // makeHistoryCards makes a new Pile of history cards from the card data.
-func makeHistoryCards() Pile {
-	var err error
-	data := parse(historyCardData)
-	if err != nil {
-		panic(err)
-	}
-	cards := make([]*HistoryCard, 50)
-	for i, r := range data {
-		if i == 0 {
-			// skip the header row
-			continue
-		}
+func makeHistoryCards(t *table) (Pile, error) {
+	cards := make(Pile, len(t.rows))
+	for i := range t.rows {
 		c := &HistoryCard{
-			Title:           r[colTitle],
-			Era:             eraNameLookup[strings.ToUpper(r[colEra])],
-			AdvancingArmies: getTribes(r[colAdvancingArmies]),
-			ResourceBonus:   getResourceBonus(r[colResourceBonus]),
-			IsWhite:         r[colIsWhite] == "TRUE",
-			IsAscendant:     r[colIsAscendant] == "TRUE",
-			IsAvaricia:      r[colIsAvaricia] == "TRUE",
-			IsSpanish:       r[colIsSpanish] == "TRUE",
-		}
-		revolt := getTribes(r[colRevolt])
-		c.Revolt = None
-		if len(revolt) > 0 {
-			c.Revolt = revolt[0]
-		}
-		if c.Number, err = strconv.Atoi(r[colNumber]); err != nil {
-			panic(err)
-		}
-		if c.ActionPoints, err = strconv.Atoi(r[colActionPoints]); err != nil {
-			panic(err)
-		}
-		if modifier := getTribes(r[colModifier]); len(modifier) > 0 {
-			c.Modifier = modifier[0]
-		}
-		cards[i-1] = c
-	}
-	return cards
-}
-
-func getTribes(s string) []Tribe {
-	if s == "" {
-		return nil
-	}
-	var ok bool
-	names := strings.Split(s, ",")
-	t := make([]Tribe, len(names))
-	for i, n := range names {
-		if t[i], ok = tribeNameLookup[n]; !ok {
-			msg := fmt.Sprintf("Bad tribe name %q in card data", n)
-			panic(msg)
+			Number:  t.int(i, colNumber),
+			Title:   t.required(i, colTitle),
+			Era:     t.era(i, colEra),
+			Effects: t.effects(i, colEffects),
 		}
+		cards[i] = c
 	}
-	return t
+	return cards, t.err()
 }
 
-func getResourceBonus(s string) []TradeGood {
-	if s == "" {
-		return nil
-	}
-	var ok bool
-	names := strings.Split(s, ",")
-	result := make([]TradeGood, len(names))
-	for i, n := range names {
-		if result[i], ok = tradeGoodNameLookup[n]; !ok {
-			msg := fmt.Sprintf("Bad trade good name %q in card data", n)
-			panic(msg)
-		}
-	}
-	return result
-}
-
-func shufflePile(cards Pile) {
+func shufflePile(cards Pile, r *rand.Rand) {
 	for i, _ := range cards {
-		j := i + rng.Intn(len(cards)-i)
+		j := i + r.Intn(len(cards)-i)
 		cards[i], cards[j] = cards[j], cards[i]
 	}
 }
@@ -172,48 +107,151 @@ func splitByEra(cards Pile) map[Era]Pile {
 	return result
 }
 
-// makeHistoryDeck makes the game's History Deck.
-func makeHistoryDeck() Pile {
+// DeckSection is one of the stacks the History Deck is built from.  The
+// sections are stacked in order, so the first section is drawn first.
+type DeckSection struct {
+	Name string
+	Size int
+	Eras map[Era]int // how many cards of each era the section started with
+}
+
+// lateStackGenerics is the number of Generic cards shuffled in with each
+// Spanish card to make the final stacks.
+const lateStackGenerics = 4
+
+// makeHistoryDeck makes the game's History Deck from a copy of the given
+// cards.  The deck is built from:
+//
+//   - an early stack of earlyCount random Hopewell cards;
+//   - a shuffled middle stack of the remaining Hopewell cards, all the
+//     Mississippian cards and the Generic cards not used below;
+//   - one final stack for each Spanish card, in card order, made by shuffling
+//     the Spanish card with four random Generic cards.
+//
+// It returns the deck and a description of each of its sections.
+func makeHistoryDeck(cards Pile, earlyCount int, r *rand.Rand) (Pile, []DeckSection) {
 	var deck Pile
-	cards := makeHistoryCards()
-	var c *HistoryCard
-	var early Pile
-	var mid Pile
-	var late Pile
-	var temp Pile
+	var sections []DeckSection
 
-	eras := splitByEra(cards)
-	hopewell := eras[Hopewell]
-	spanish := eras[Spanish]
-	mississippian := eras[Mississippian]
-	generic := eras[Mississippian]
-
-	// make the early deck from 10 random Hopewell cards
-	shufflePile(hopewell)
-	for i := 0; i < 10; i++ {
-		c, hopewell = drawFromPile(hopewell)
-		early = append(early, c)
+	addSection := func(name string, p Pile) {
+		s := DeckSection{Name: name, Size: len(p), Eras: make(map[Era]int)}
+		for _, c := range p {
+			s.Eras[c.Era]++
+		}
+		sections = append(sections, s)
+		deck = append(deck, p...)
 	}
 
-	// This logic depends on Coosa being the first Spanish card
-	// and The Spanish being the second.
-	for i := 0; i < 2; i++ {
-		c, spanish = drawFromPile(spanish)
-		temp = append(temp, c)
-		for j := 0; j < 4; j++ {
-			c, generic = drawFromPile(generic)
-			temp = append(temp, c)
-		}
-		shufflePile(temp)
-		late = append(late, temp...)
-		temp = nil
+	eras := splitByEra(cards)
+	hopewell := append(Pile(nil), eras[Hopewell]...)
+	generic := append(Pile(nil), eras[Generic]...)
+	spanish := append(Pile(nil), eras[Spanish]...)
+	sort.Sort(byNumber(spanish))
+
+	shufflePile(hopewell, r)
+	shufflePile(generic, r)
+	late := make([]Pile, len(spanish))
+	for i, c := range spanish {
+		late[i] = append(Pile{c}, generic[:lateStackGenerics]...)
+		generic = generic[lateStackGenerics:]
+		shufflePile(late[i], r)
 	}
 
-	mid = append(hopewell, mississippian...)
+	var mid Pile
+	mid = append(mid, hopewell[earlyCount:]...)
+	mid = append(mid, eras[Mississippian]...)
 	mid = append(mid, generic...)
-	shufflePile(mid)
+	shufflePile(mid, r)
+
+	addSection("early", hopewell[:earlyCount])
+	addSection("middle", mid)
+	for i, p := range late {
+		addSection(fmt.Sprintf("late %d", i+1), p)
+	}
+	return deck, sections
+}
+
+type byNumber Pile
+
+func (p byNumber) Len() int           { return len(p) }
+func (p byNumber) Less(i, j int) bool { return p[i].Number < p[j].Number }
+func (p byNumber) Swap(i, j int)      { p[i], p[j] = p[j], p[i] }
+
+// DeckPosition describes a card still in the History Deck.
+type DeckPosition struct {
+	Position int // 1 for the next card to be drawn
+	Section  string
+	Number   int
+	Title    string
+	Era      Era
+}
+
+// SectionComposition describes what remains of one section of the deck.
+type SectionComposition struct {
+	Name  string
+	First int // position of the section's next card; 0 if it's used up
+	Last  int
+	Eras  map[Era]int
+}
+
+// DeckComposition describes the cards remaining in the History Deck, by era
+// and by position.
+type DeckComposition struct {
+	Remaining int
+	Eras      map[Era]int
+	Sections  []SectionComposition
+	Cards     []DeckPosition
+}
+
+// sectionRanges returns, for each of the deck's sections, the start and end
+// of what remains of it in the History Deck.
+func (g *Game) sectionRanges() [][2]int {
+	total := 0
+	for _, s := range g.DeckSections {
+		total += s.Size
+	}
+	skip := total - len(g.HistoryDeck) // cards already drawn
+	ranges := make([][2]int, len(g.DeckSections))
+	pos := 0
+	for i, s := range g.DeckSections {
+		n := s.Size - skip
+		if n < 0 {
+			n = 0
+		}
+		skip -= s.Size - n
+		ranges[i] = [2]int{pos, pos + n}
+		pos += n
+	}
+	return ranges
+}
 
-	deck = append(early, mid...)
-	deck = append(deck, late...)
-	return deck
+// DeckComposition reports the composition of the remaining History Deck.
+// It reveals the order of the deck, so it's meant for checking the setup,
+// not for showing to a player.
+func (g *Game) DeckComposition() DeckComposition {
+	dc := DeckComposition{
+		Remaining: len(g.HistoryDeck),
+		Eras:      make(map[Era]int),
+	}
+	for i, r := range g.sectionRanges() {
+		s := g.DeckSections[i]
+		sc := SectionComposition{Name: s.Name, Eras: make(map[Era]int)}
+		if r[1] > r[0] {
+			sc.First, sc.Last = r[0]+1, r[1]
+		}
+		for pos := r[0]; pos < r[1]; pos++ {
+			c := g.HistoryDeck[pos]
+			sc.Eras[c.Era]++
+			dc.Eras[c.Era]++
+			dc.Cards = append(dc.Cards, DeckPosition{
+				Position: pos + 1,
+				Section:  s.Name,
+				Number:   c.Number,
+				Title:    c.Title,
+				Era:      c.Era,
+			})
+		}
+		dc.Sections = append(dc.Sections, sc)
+	}
+	return dc
 }
@@ -0,0 +1,100 @@
+package mb
+
+import (
+	"reflect"
+	"testing"
+)
+
+func newTestGame(t *testing.T, o GameOptions) *Game {
+	t.Helper()
+	if o.Seed == 0 {
+		o.Seed = 1
+	}
+	g, err := NewGame(o)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return g
+}
+
+func TestDeckComposition(t *testing.T) {
+	for _, early := range []int{0, 8, 10, 12} {
+		g := newTestGame(t, GameOptions{EarlyHopewellCards: Int(early)})
+		dc := g.DeckComposition()
+		if dc.Remaining != cardCount {
+			t.Errorf("early %d: %d cards in the deck; want %d", early, dc.Remaining, cardCount)
+		}
+		want := []SectionComposition{
+			{Name: "early", Eras: map[Era]int{Hopewell: early}},
+			{Name: "middle", Eras: map[Era]int{Hopewell: 12 - early, Mississippian: 12, Generic: 24 - 2*lateStackGenerics}},
+			{Name: "late 1", Eras: map[Era]int{Spanish: 1, Generic: lateStackGenerics}},
+			{Name: "late 2", Eras: map[Era]int{Spanish: 1, Generic: lateStackGenerics}},
+		}
+		if len(dc.Sections) != len(want) {
+			t.Fatalf("early %d: %d sections; want %d", early, len(dc.Sections), len(want))
+		}
+		pos := 0
+		for i, w := range want {
+			s := dc.Sections[i]
+			if s.Name != w.Name {
+				t.Errorf("early %d: section %d is %q; want %q", early, i, s.Name, w.Name)
+			}
+			size := 0
+			for _, e := range []Era{Hopewell, Mississippian, Spanish, Generic} {
+				if s.Eras[e] != w.Eras[e] {
+					t.Errorf("early %d: section %s has %d %s cards; want %d", early, s.Name, s.Eras[e], e, w.Eras[e])
+				}
+				size += w.Eras[e]
+			}
+			if size > 0 && (s.First != pos+1 || s.Last != pos+size) {
+				t.Errorf("early %d: section %s is at %d-%d; want %d-%d", early, s.Name, s.First, s.Last, pos+1, pos+size)
+			}
+			if g.DeckSections[i].Size != size {
+				t.Errorf("early %d: section %s has size %d; want %d", early, s.Name, g.DeckSections[i].Size, size)
+			}
+			pos += size
+		}
+		for i, c := range dc.Cards {
+			if c.Position != i+1 || c.Number != g.HistoryDeck[i].Number {
+				t.Errorf("early %d: card %d is %+v; want card %d", early, i+1, c, g.HistoryDeck[i].Number)
+			}
+		}
+	}
+}
+
+func TestSpanishCardsInOrder(t *testing.T) {
+	g := newTestGame(t, GameOptions{})
+	var spanish []int
+	for _, c := range g.HistoryDeck {
+		if c.Era == Spanish {
+			spanish = append(spanish, c.Number)
+		}
+	}
+	if len(spanish) != 2 || spanish[0] > spanish[1] {
+		t.Errorf("Spanish cards in the deck: %v; want both, in card order", spanish)
+	}
+}
+
+func TestSectionRangesAfterDrawing(t *testing.T) {
+	tests := []struct {
+		drawn int
+		want  [][2]int
+	}{
+		{0, [][2]int{{0, 10}, {10, 40}, {40, 45}, {45, 50}}},
+		{1, [][2]int{{0, 9}, {9, 39}, {39, 44}, {44, 49}}},
+		{10, [][2]int{{0, 0}, {0, 30}, {30, 35}, {35, 40}}},
+		{12, [][2]int{{0, 0}, {0, 28}, {28, 33}, {33, 38}}},
+		{40, [][2]int{{0, 0}, {0, 0}, {0, 5}, {5, 10}}},
+		{47, [][2]int{{0, 0}, {0, 0}, {0, 0}, {0, 3}}},
+		{50, [][2]int{{0, 0}, {0, 0}, {0, 0}, {0, 0}}},
+	}
+	for _, tt := range tests {
+		g := newTestGame(t, GameOptions{EarlyHopewellCards: Int(10)})
+		for i := 0; i < tt.drawn; i++ {
+			g.drawHistoryCard()
+		}
+		if got := g.sectionRanges(); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("after %d cards, the sections are at %v; want %v", tt.drawn, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package mb
+
+import "math/rand"
+
+// source is a random number source whose state can be copied, so that a
+// clone of a game rolls the same dice as the original.  It's SplitMix64.
+type source struct {
+	state uint64
+}
+
+func (s *source) Seed(seed int64) {
+	s.state = uint64(seed)
+}
+
+func (s *source) Uint64() uint64 {
+	s.state += 0x9e3779b97f4a7c15
+	z := s.state
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return z ^ (z >> 31)
+}
+
+func (s *source) Int63() int64 {
+	return int64(s.Uint64() >> 1)
+}
+
+// seed gives the game a new random number generator.
+func (g *Game) seed(seed int64) {
+	g.src = &source{state: uint64(seed)}
+	g.rng = rand.New(g.src)
+}
+
+// Clone returns a copy of the game that shares nothing with it, including
+// the state of its random number generator: the clone plays out exactly as
+// the original would, and changing one doesn't change the other.
+func (g *Game) Clone() *Game {
+	return g.clone(true)
+}
+
+// simulationCopy returns a clone for playing out in a simulation.  It leaves
+// out the log and the requests handled so far, which a simulation doesn't
+// need and which only grow as the game goes on, doesn't log to the console
+// and doesn't copy itself before each request to roll back to.
+func (g *Game) simulationCopy() *Game {
+	c := g.clone(false)
+	c.LogToConsole, c.Rollback = false, false
+	return c
+}
+
+// clone copies the game, with its log and requests if history is set.
+func (g *Game) clone(history bool) *Game {
+	c := *g
+	src := *g.src
+	c.src = &src
+	c.rng = rand.New(c.src)
+
+	// counters and cards are copied once each, so that the same counter in
+	// two places stays the same counter
+	counters := make(map[*ChiefdomCounter]*ChiefdomCounter)
+	counter := func(k *ChiefdomCounter) *ChiefdomCounter {
+		if k == nil {
+			return nil
+		}
+		if counters[k] == nil {
+			kc := *k
+			counters[k] = &kc
+		}
+		return counters[k]
+	}
+	cards := make(map[*HistoryCard]*HistoryCard)
+	card := func(h *HistoryCard) *HistoryCard {
+		if h == nil {
+			return nil
+		}
+		if cards[h] == nil {
+			hc := *h
+			hc.Effects = make([]Effect, len(h.Effects))
+			for i, e := range h.Effects {
+				hc.Effects[i] = e.copy()
+			}
+			cards[h] = &hc
+		}
+		return cards[h]
+	}
+
+	c.Board = g.Board.clone(counter, card)
+	c.HistoryDeck = make(Pile, len(g.HistoryDeck))
+	for i, h := range g.HistoryDeck {
+		c.HistoryDeck[i] = card(h)
+	}
+	c.Cup = make(Cup, len(g.Cup))
+	for i, k := range g.Cup {
+		c.Cup[i] = counter(k)
+	}
+
+	if g.Response != nil {
+		r := *g.Response
+		c.Response = &r
+	}
+	if g.Action != nil {
+		a := *g.Action
+		spec := *g.Action.Spec
+		a.Spec = &spec
+		c.Action = &a
+	}
+	c.AdvancingArmies = append([]Tribe(nil), g.AdvancingArmies...)
+	c.Log, c.Requests = nil, nil
+	if history {
+		c.Log = append([]string(nil), g.Log...)
+		c.Requests = append([]Request(nil), g.Requests...)
+	}
+	c.Options = g.Options.copy()
+	c.DeckSections = make([]DeckSection, len(g.DeckSections))
+	for i, s := range g.DeckSections {
+		c.DeckSections[i] = s
+		c.DeckSections[i].Eras = make(map[Era]int)
+		for e, n := range s.Eras {
+			c.DeckSections[i].Eras[e] = n
+		}
+	}
+	return &c
+}
+
+// clone copies the board, using the given functions to copy the counters
+// and cards on it.
+func (b Board) clone(counter func(*ChiefdomCounter) *ChiefdomCounter, card func(*HistoryCard) *HistoryCard) Board {
+	c := b
+	c.Card = card(b.Card)
+	c.Palisades = append([]Palisade(nil), b.Palisades...)
+	c.Lands = append([]Land(nil), b.Lands...)
+	c.PeacePipes = append([]bool(nil), b.PeacePipes...)
+
+	c.Chiefdoms = make([]*Chiefdom, len(b.Chiefdoms))
+	for i, ch := range b.Chiefdoms {
+		if ch != nil {
+			cc := *ch
+			cc.Counter = counter(ch.Counter)
+			c.Chiefdoms[i] = &cc
+		}
+	}
+
+	c.Hostiles = make([]*HostileMarker, len(b.Hostiles))
+	for i, h := range b.Hostiles {
+		hc := *h
+		c.Hostiles[i] = &hc
+	}
+
+	if b.WarpathActions != nil {
+		c.WarpathActions = make(map[string][]FrontEndAction, len(b.WarpathActions))
+		for k, v := range b.WarpathActions {
+			c.WarpathActions[k] = append([]FrontEndAction(nil), v...)
+		}
+	}
+	if b.Economy != nil {
+		e := *b.Economy
+		e.ResourceBonuses = append([]ResourceBonus(nil), b.Economy.ResourceBonuses...)
+		c.Economy = &e
+	}
+	return c
+}
@@ -0,0 +1,93 @@
+package mb
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// startedTestGame returns a game a few turns in.
+func startedTestGame(t *testing.T) *Game {
+	t.Helper()
+	g := newTestGame(t, GameOptions{})
+	g.StartGame()
+	for i := 0; i < 3; i++ {
+		g.HandleRequest(Request{Input: "pas"})
+	}
+	return g
+}
+
+// snapshot records a game's state as JSON, so that it can be compared
+// without relying on Clone.
+func snapshot(t *testing.T, g *Game) string {
+	t.Helper()
+	b, err := json.Marshal([]interface{}{
+		g.Board, g.HistoryDeck, g.Cup, g.Requests, g.Log, g.Options,
+		g.DeckSections, g.AdvancingArmies, g.RevoltingTribe, g.src.state,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+func TestCloneIsIndependent(t *testing.T) {
+	g := startedTestGame(t)
+	want := snapshot(t, g)
+	if got := snapshot(t, g.Clone()); got != want {
+		t.Fatal("the clone differs from the original")
+	}
+
+	c := g.Clone()
+	c.Board.ActionPoints += 5
+	c.Board.PeacePipes[0] = !c.Board.PeacePipes[0]
+	c.Board.Palisades[0].Value = 99
+	for _, ch := range c.Board.Chiefdoms {
+		if ch != nil {
+			ch.IsControlled = !ch.IsControlled
+			ch.Counter.Plain.Value = 9
+		}
+	}
+	c.Board.findHostile(HoChunk).LandIndex = toLandIndex(HoChunk, 2)
+	c.Board.Card.Effects[0].AP = 99
+	c.HistoryDeck[0].Effects[0].AP = 99
+	c.drawHistoryCard()
+	c.Cup[0].Plain.Value = 9
+	c.Cup = c.Cup[1:]
+	c.Options.HostileBattleValues[0] = 6
+	c.DeckSections[0].Eras[Hopewell] = 99
+	for i := 0; i < 10; i++ {
+		c.die()
+	}
+	c.HandleRequest(Request{Input: "pas"})
+
+	if snapshot(t, g) != want {
+		t.Error("changing the clone changed the original")
+	}
+}
+
+func TestCloneKeepsSharedPointers(t *testing.T) {
+	c := startedTestGame(t).Clone()
+	h := c.Board.findHostile(Cherokee)
+	if h != c.Board.Hostiles[int(Cherokee)] {
+		t.Error("findHostile returns a different marker from the one in Board.Hostiles")
+	}
+	h.LandIndex = toLandIndex(Cherokee, 3)
+	if len(c.Board.hostilesAt(h.LandIndex)) != 1 {
+		t.Error("moving the clone's marker didn't move it on the clone's board")
+	}
+}
+
+func TestCloneRollsTheSameDice(t *testing.T) {
+	g := startedTestGame(t)
+	c := g.Clone()
+	for i := 0; i < 100; i++ {
+		if a, b := g.die(), c.die(); a != b {
+			t.Fatalf("roll %d: the original rolled %d and the clone %d", i, a, b)
+		}
+	}
+	g.HandleRequest(Request{Input: "pas"})
+	c.HandleRequest(Request{Input: "pas"})
+	if snapshot(t, g) != snapshot(t, c) {
+		t.Error("the original and the clone played the same request differently")
+	}
+}
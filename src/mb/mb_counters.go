@@ -1,9 +1,8 @@
 package mb
 
 import (
-	"encoding/csv"
-	"strconv"
-	"strings"
+	"math/rand"
+	"sort"
 )
 
 var counterData = `Good,PlainValue,PlainGreenBird,MoundedValue,MoundedGreenBird
@@ -41,31 +40,26 @@ const (
 	colMoundedGreenBird
 )
 
-// parse parses CSV data from a string into a slice of slices.
-func parse(data string) [][]string {
-	r := strings.NewReader(data)
-	c := csv.NewReader(r)
-	c.TrailingComma = true
-	result, err := c.ReadAll()
-	if err != nil {
-		panic(err)
+// makeCup fills a new cup with copies of the given counters and shuffles it.
+func makeCup(counters Cup, r *rand.Rand) Cup {
+	cup := make(Cup, len(counters))
+	for i, c := range counters {
+		cc := *c
+		cup[i] = &cc
 	}
-	return result
-}
-
-func makeCup() Cup {
-	cup := makeChiefdomCounters()
-	shuffleCup(cup)
+	shuffleCup(cup, r)
 	return cup
 }
 
-func shuffleCup(cup Cup) {
+func shuffleCup(cup Cup, r *rand.Rand) {
 	for i, _ := range cup {
-		j := i + rng.Intn(len(cup)-i)
+		j := i + r.Intn(len(cup)-i)
 		cup[i], cup[j] = cup[j], cup[i]
 	}
 }
 
+// drawFromCup draws the top counter from the cup.  It returns nil if the cup
+// is empty.
 func drawFromCup(cup Cup) (*ChiefdomCounter, Cup) {
 	if len(cup) == 0 {
 		return nil, cup
@@ -75,31 +69,64 @@ func drawFromCup(cup Cup) (*ChiefdomCounter, Cup) {
 	return c, cup
 }
 
-func makeChiefdomCounters() Cup {
-	var err error
-	data := parse(counterData)
-	counters := make(Cup, len(data)-1)
-	for i, r := range data {
-		if i == 0 {
-			// skip the header row
-			continue
+// returnToCup puts a counter back in the cup at a random place, so that it
+// may be drawn again.
+func (g *Game) returnToCup(c *ChiefdomCounter) {
+	i := g.rng.Intn(len(g.Cup) + 1)
+	g.Cup = append(g.Cup, nil)
+	copy(g.Cup[i+1:], g.Cup[i:])
+	g.Cup[i] = c
+}
+
+// CupContents returns copies of the counters left in the cup, sorted by
+// trade good and value so that they don't give away the order they will be
+// drawn in.
+func (g *Game) CupContents() []ChiefdomCounter {
+	counters := make([]ChiefdomCounter, len(g.Cup))
+	for i, c := range g.Cup {
+		counters[i] = *c
+	}
+	sort.Slice(counters, func(i, j int) bool {
+		a, b := counters[i], counters[j]
+		switch {
+		case a.Good != b.Good:
+			return a.Good < b.Good
+		case a.Plain.Value != b.Plain.Value:
+			return a.Plain.Value < b.Plain.Value
+		default:
+			return a.Mounded.Value < b.Mounded.Value
 		}
+	})
+	return counters
+}
+
+var counterColumns = []string{
+	"Good", "PlainValue", "PlainGreenBird", "MoundedValue", "MoundedGreenBird",
+}
+
+func makeChiefdomCounters(t *table) (Cup, error) {
+	counters := make(Cup, len(t.rows))
+	for i := range t.rows {
 		c := &ChiefdomCounter{
-			Good: tradeGoodNameLookup[r[colGood]],
 			Plain: ChiefdomCounterFace{
-				IsGreenBird: r[colPlainGreenBird] == "TRUE",
+				Value:       t.int(i, colPlainValue),
+				IsGreenBird: t.bool(i, colPlainGreenBird),
 			},
 			Mounded: ChiefdomCounterFace{
-				IsGreenBird: r[colMoundedGreenBird] == "TRUE",
+				Value:       t.int(i, colMoundedValue),
+				IsGreenBird: t.bool(i, colMoundedGreenBird),
 			},
 		}
-		if c.Plain.Value, err = strconv.Atoi(r[colPlainValue]); err != nil {
-			panic(err)
-		}
-		if c.Mounded.Value, err = strconv.Atoi(r[colMoundedValue]); err != nil {
-			panic(err)
+		if t.required(i, colGood) != "" {
+			switch goods := t.tradeGoods(i, colGood); len(goods) {
+			case 0:
+			case 1:
+				c.Good = goods[0]
+			default:
+				t.fail(i, colGood, "a counter has only one trade good")
+			}
 		}
-		counters[i-1] = c
+		counters[i] = c
 	}
-	return counters
+	return counters, t.err()
 }
@@ -0,0 +1,103 @@
+package mb
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReturnToCup(t *testing.T) {
+	g := newTestGame(t, GameOptions{})
+	before := append(Cup(nil), g.Cup...)
+	c := &ChiefdomCounter{Good: Obsidian}
+	g.returnToCup(c)
+	if len(g.Cup) != len(before)+1 {
+		t.Fatalf("the cup has %d counters; want %d", len(g.Cup), len(before)+1)
+	}
+	var rest Cup
+	found := 0
+	for _, cc := range g.Cup {
+		if cc == c {
+			found++
+		} else {
+			rest = append(rest, cc)
+		}
+	}
+	if found != 1 || !reflect.DeepEqual(rest, before) {
+		t.Error("the counter wasn't put back in the cup once, leaving the others in order")
+	}
+
+	g.Cup = nil
+	g.returnToCup(c)
+	if len(g.Cup) != 1 || g.Cup[0] != c {
+		t.Errorf("returning a counter to an empty cup left %v", g.Cup)
+	}
+}
+
+func TestReturnToCupPlacesAtRandom(t *testing.T) {
+	places := make(map[int]bool)
+	for seed := int64(1); seed <= 20; seed++ {
+		g := newTestGame(t, GameOptions{Seed: seed})
+		c := &ChiefdomCounter{Good: Obsidian}
+		g.returnToCup(c)
+		for i, cc := range g.Cup {
+			if cc == c {
+				places[i] = true
+			}
+		}
+	}
+	if len(places) < 2 {
+		t.Errorf("returned counters always went to %v", places)
+	}
+}
+
+func TestCupContents(t *testing.T) {
+	g := newTestGame(t, GameOptions{})
+	contents := g.CupContents()
+	if len(contents) != len(g.Cup) {
+		t.Fatalf("%d counters listed; the cup has %d", len(contents), len(g.Cup))
+	}
+	for i := 1; i < len(contents); i++ {
+		a, b := contents[i-1], contents[i]
+		if a.Good > b.Good || a.Good == b.Good && (a.Plain.Value > b.Plain.Value ||
+			a.Plain.Value == b.Plain.Value && a.Mounded.Value > b.Mounded.Value) {
+			t.Errorf("%v is listed before %v", a, b)
+		}
+	}
+
+	// the list is a copy
+	want := snapshot(t, g)
+	contents[0].Good = Obsidian
+	contents[0].Plain.Value = 6
+	if snapshot(t, g) != want {
+		t.Error("changing the list changed the cup")
+	}
+
+	g.Cup = nil
+	if n := len(g.CupContents()); n != 0 {
+		t.Errorf("an empty cup lists %d counters", n)
+	}
+}
+
+func TestDrawChiefdomCounterFromEmptyCup(t *testing.T) {
+	g := newTestGame(t, GameOptions{})
+	l := g.Board.Lands[toLandIndex(Natchez, 2)]
+	g.Cup = nil
+	if g.drawChiefdomCounter(l) {
+		t.Error("a chiefdom was placed from an empty cup")
+	}
+	if g.Board.Chiefdoms[l.Index] != nil {
+		t.Errorf("%s has a chiefdom", l.Name)
+	}
+	if n := len(g.Log); n == 0 || !strings.Contains(g.Log[n-1], "The cup is empty") {
+		t.Error("drawing from the empty cup wasn't logged")
+	}
+
+	g.Cup = Cup{&ChiefdomCounter{Good: Mica, Plain: ChiefdomCounterFace{Value: 3}}}
+	if !g.drawChiefdomCounter(l) || g.Board.Chiefdoms[l.Index] == nil || len(g.Cup) != 0 {
+		t.Error("the last counter in the cup wasn't placed")
+	}
+	if g.drawChiefdomCounter(l) {
+		t.Error("a second chiefdom was placed on the same land")
+	}
+}
@@ -0,0 +1,382 @@
+package mb
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// GameData holds the cards, counters and lands a game is built from.  By
+// default these come from the data embedded in the program, but any of them
+// can be loaded from a CSV or JSON file instead.
+type GameData struct {
+	Cards    Pile
+	Counters Cup
+	Lands    []Land // indexed by Land.Index
+}
+
+// DataError describes a problem with a value in a data file.
+type DataError struct {
+	Source string // the file name, or a description of the embedded data
+	Row    int    // the data row, counting from 1; 0 for the file as a whole
+	Column string // empty if the problem isn't with a single column
+	Err    error
+}
+
+func (e *DataError) Error() string {
+	switch {
+	case e.Row == 0:
+		return fmt.Sprintf("%s: %s", e.Source, e.Err)
+	case e.Column == "":
+		return fmt.Sprintf("%s: row %d: %s", e.Source, e.Row, e.Err)
+	}
+	return fmt.Sprintf("%s: row %d, column %s: %s", e.Source, e.Row, e.Column, e.Err)
+}
+
+// DataErrors is the list of problems found while loading a data file.
+type DataErrors []*DataError
+
+func (e DataErrors) Error() string {
+	lines := make([]string, len(e))
+	for i, err := range e {
+		lines[i] = err.Error()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// LoadGameData loads the game data from the given card, counter and board
+// files.  An empty file name selects the embedded data for that file.
+func LoadGameData(cardsFile, countersFile, boardFile string) (*GameData, error) {
+	var d GameData
+	var errs DataErrors
+
+	t, err := loadTable("embedded card data", historyCardData, cardsFile, historyCardColumns)
+	if err == nil {
+		d.Cards, err = makeHistoryCards(t)
+	}
+	errs = appendDataErrors(errs, err)
+
+	t, err = loadTable("embedded counter data", counterData, countersFile, counterColumns)
+	if err == nil {
+		d.Counters, err = makeChiefdomCounters(t)
+	}
+	errs = appendDataErrors(errs, err)
+
+	t, err = loadTable("embedded board data", boardData, boardFile, boardColumns)
+	if err == nil {
+		d.Lands, err = makeLands(t)
+	}
+	errs = appendDataErrors(errs, err)
+
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return &d, nil
+}
+
+// loadGameData loads and validates the data files named in the options.
+func loadGameData(o GameOptions) (*GameData, error) {
+	d, err := LoadGameData(o.CardsFile, o.CountersFile, o.BoardFile)
+	if err != nil {
+		return nil, err
+	}
+	if errs := d.Validate(); errs != nil {
+		return nil, errs
+	}
+	return d, nil
+}
+
+func appendDataErrors(errs DataErrors, err error) DataErrors {
+	switch e := err.(type) {
+	case nil:
+	case DataErrors:
+		errs = append(errs, e...)
+	case *DataError:
+		errs = append(errs, e)
+	default:
+		errs = append(errs, &DataError{Err: err})
+	}
+	return errs
+}
+
+// table is a data file that has been read into rows of strings, one string
+// for each of the expected columns, in the order the columns were expected.
+type table struct {
+	source  string
+	columns []string
+	rows    [][]string
+	errs    DataErrors
+}
+
+// loadTable reads the named file, or the embedded CSV data if name is empty.
+func loadTable(embeddedName, embedded, name string, columns []string) (*table, error) {
+	if name == "" {
+		return readCSV(embeddedName, strings.NewReader(embedded), columns)
+	}
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, &DataError{Source: name, Err: err}
+	}
+	defer f.Close()
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".csv":
+		return readCSV(name, f, columns)
+	case ".json":
+		return readJSON(name, f, columns)
+	}
+	return nil, &DataError{Source: name, Err: fmt.Errorf("unknown file type; must be .csv or .json")}
+}
+
+// readCSV reads CSV data whose first line is a header naming its columns.
+// The columns may be in any order, but every expected column must be present
+// and no others.
+func readCSV(source string, r io.Reader, columns []string) (*table, error) {
+	c := csv.NewReader(r)
+	c.TrimLeadingSpace = true
+	records, err := c.ReadAll()
+	if err != nil {
+		return nil, &DataError{Source: source, Err: err}
+	}
+	if len(records) == 0 {
+		return nil, &DataError{Source: source, Err: fmt.Errorf("no header row")}
+	}
+	t := &table{source: source, columns: columns}
+	pos := make([]int, len(columns))
+	for i := range pos {
+		pos[i] = -1
+	}
+	for i, h := range records[0] {
+		if col := columnIndex(columns, h); col < 0 {
+			t.errs = append(t.errs, &DataError{Source: source, Column: h, Err: fmt.Errorf("unknown column %q", h)})
+		} else {
+			pos[col] = i
+		}
+	}
+	for i, p := range pos {
+		if p < 0 {
+			t.errs = append(t.errs, &DataError{Source: source, Err: fmt.Errorf("missing column %q", columns[i])})
+		}
+	}
+	if len(t.errs) > 0 {
+		return nil, t.errs
+	}
+	for _, rec := range records[1:] {
+		row := make([]string, len(columns))
+		for i, p := range pos {
+			row[i] = strings.TrimSpace(rec[p])
+		}
+		t.rows = append(t.rows, row)
+	}
+	return t, nil
+}
+
+// readJSON reads a JSON array of objects, one for each row, keyed by column
+// name.  Values may be strings, numbers, booleans or arrays of strings; a
+// missing key is the same as an empty CSV field.
+func readJSON(source string, r io.Reader, columns []string) (*table, error) {
+	var records []map[string]interface{}
+	if err := json.NewDecoder(r).Decode(&records); err != nil {
+		return nil, &DataError{Source: source, Err: err}
+	}
+	t := &table{source: source, columns: columns}
+	for n, rec := range records {
+		row := make([]string, len(columns))
+		// keys are taken in order, so that problems are reported in the
+		// same order every time
+		keys := make([]string, 0, len(rec))
+		for k := range rec {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			v := rec[k]
+			col := columnIndex(columns, k)
+			if col < 0 {
+				t.errs = append(t.errs, &DataError{Source: source, Row: n + 1, Column: k, Err: fmt.Errorf("unknown column %q", k)})
+				continue
+			}
+			s, err := jsonValueString(v)
+			if err != nil {
+				t.errs = append(t.errs, &DataError{Source: source, Row: n + 1, Column: k, Err: err})
+			}
+			row[col] = s
+		}
+		t.rows = append(t.rows, row)
+	}
+	// problems with single values are reported along with any found when
+	// the rows are converted
+	return t, nil
+}
+
+// jsonValueString converts a JSON value into the string the same value would
+// have in a CSV file.
+func jsonValueString(v interface{}) (string, error) {
+	switch v := v.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return strings.TrimSpace(v), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case bool:
+		if v {
+			return "TRUE", nil
+		}
+		return "", nil
+	case []interface{}:
+		items := make([]string, len(v))
+		for i, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return "", fmt.Errorf("list items must be strings, not %v", item)
+			}
+			items[i] = strings.TrimSpace(s)
+		}
+		return strings.Join(items, ","), nil
+	}
+	return "", fmt.Errorf("unsupported value %v", v)
+}
+
+func columnIndex(columns []string, name string) int {
+	for i, c := range columns {
+		if strings.EqualFold(c, strings.TrimSpace(name)) {
+			return i
+		}
+	}
+	return -1
+}
+
+// fail records a problem with a column of the given row, counting from 0.
+func (t *table) fail(row, col int, f string, args ...interface{}) {
+	t.errs = append(t.errs, &DataError{
+		Source: t.source,
+		Row:    row + 1,
+		Column: t.columns[col],
+		Err:    fmt.Errorf(f, args...),
+	})
+}
+
+// err returns the problems found so far, or nil if there weren't any.
+func (t *table) err() error {
+	if len(t.errs) == 0 {
+		return nil
+	}
+	return t.errs
+}
+
+// The following helpers convert one value of a row, recording a problem
+// and returning the zero value if it can't be converted.
+
+func (t *table) required(row, col int) string {
+	s := t.rows[row][col]
+	if s == "" {
+		t.fail(row, col, "value is required")
+	}
+	return s
+}
+
+func (t *table) int(row, col int) int {
+	s := t.required(row, col)
+	if s == "" {
+		return 0
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		t.fail(row, col, "%q is not a number", s)
+	}
+	return n
+}
+
+func (t *table) bool(row, col int) bool {
+	switch s := strings.ToUpper(t.rows[row][col]); s {
+	case "", "FALSE":
+		return false
+	case "TRUE":
+		return true
+	}
+	t.fail(row, col, "%q must be TRUE, FALSE or blank", t.rows[row][col])
+	return false
+}
+
+func (t *table) era(row, col int) Era {
+	s := t.required(row, col)
+	if s == "" {
+		return 0
+	}
+	e, ok := eraNameLookup[strings.ToUpper(s)]
+	if !ok {
+		t.fail(row, col, "unknown era %q", s)
+	}
+	return e
+}
+
+func (t *table) tribes(row, col int) []Tribe {
+	s := t.rows[row][col]
+	if s == "" {
+		return nil
+	}
+	var result []Tribe
+	for _, n := range strings.Split(s, ",") {
+		n = strings.TrimSpace(n)
+		if tribe, ok := tribeNameLookup[n]; ok {
+			result = append(result, tribe)
+		} else {
+			t.fail(row, col, "unknown tribe %q", n)
+		}
+	}
+	return result
+}
+
+// tribe converts a value that may name at most one tribe, returning
+// blank if the value is empty.
+func (t *table) tribe(row, col int, blank Tribe) Tribe {
+	switch found := t.tribes(row, col); len(found) {
+	case 0:
+		return blank
+	case 1:
+		return found[0]
+	}
+	t.fail(row, col, "%q names more than one tribe", t.rows[row][col])
+	return blank
+}
+
+func (t *table) tradeGoods(row, col int) []TradeGood {
+	s := t.rows[row][col]
+	if s == "" {
+		return nil
+	}
+	var result []TradeGood
+	for _, n := range strings.Split(s, ",") {
+		n = strings.TrimSpace(n)
+		if good, ok := tradeGoodNameLookup[n]; ok {
+			result = append(result, good)
+		} else {
+			t.fail(row, col, "unknown trade good %q", n)
+		}
+	}
+	return result
+}
+
+// effects converts a list of card effects, each written as described for
+// parseEffect.
+func (t *table) effects(row, col int) []Effect {
+	s := t.rows[row][col]
+	if s == "" {
+		return nil
+	}
+	var result []Effect
+	for _, n := range strings.Split(s, ",") {
+		if e, err := parseEffect(n); err == nil {
+			result = append(result, e)
+		} else {
+			t.fail(row, col, "%s", err)
+		}
+	}
+	return result
+}
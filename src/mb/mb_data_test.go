@@ -0,0 +1,121 @@
+package mb
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeTestFile writes a data file into a temporary directory and returns
+// its name.
+func writeTestFile(t *testing.T, name, data string) string {
+	t.Helper()
+	name = filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(name, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return name
+}
+
+// loadErrors loads the given counter file with the embedded cards and
+// board, and returns the problems found.
+func loadErrors(t *testing.T, countersFile string) DataErrors {
+	t.Helper()
+	_, err := LoadGameData("", countersFile, "")
+	if err == nil {
+		t.Fatal("loading the counters succeeded; want errors")
+	}
+	errs, ok := err.(DataErrors)
+	if !ok {
+		t.Fatalf("loading the counters returned %T %v; want DataErrors", err, err)
+	}
+	return errs
+}
+
+// checkDataErrors checks that the errors are for the given rows and columns,
+// in order, and mention the given text.
+func checkDataErrors(t *testing.T, errs DataErrors, want []DataError) {
+	t.Helper()
+	if len(errs) != len(want) {
+		t.Fatalf("got %d errors; want %d:\n%v", len(errs), len(want), errs)
+	}
+	for i, w := range want {
+		e := errs[i]
+		if e.Row != w.Row || e.Column != w.Column || !strings.Contains(e.Error(), w.Err.Error()) {
+			t.Errorf("error %d is %q at row %d, column %q; want %q at row %d, column %q",
+				i, e, e.Row, e.Column, w.Err, w.Row, w.Column)
+		}
+	}
+}
+
+type errText string
+
+func (e errText) Error() string { return string(e) }
+
+func TestLoadEmbeddedData(t *testing.T) {
+	d, err := LoadGameData("", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(d.Cards) != cardCount || len(d.Counters) != counterCount || len(d.Lands) != LandCount {
+		t.Errorf("loaded %d cards, %d counters and %d lands; want %d, %d and %d",
+			len(d.Cards), len(d.Counters), len(d.Lands), cardCount, counterCount, LandCount)
+	}
+}
+
+func TestLoadCSVErrors(t *testing.T) {
+	name := writeTestFile(t, "counters.csv", `Good,PlainValue,PlainGreenBird,MoundedValue,MoundedGreenBird
+Hides,2,,4,TRUE
+Gold,x,maybe,4,
+Mica,,,3,`)
+	checkDataErrors(t, loadErrors(t, name), []DataError{
+		{Row: 2, Column: "PlainValue", Err: errText(`"x" is not a number`)},
+		{Row: 2, Column: "PlainGreenBird", Err: errText(`"maybe" must be TRUE, FALSE or blank`)},
+		{Row: 2, Column: "Good", Err: errText(`unknown trade good "Gold"`)},
+		{Row: 3, Column: "PlainValue", Err: errText("value is required")},
+	})
+}
+
+func TestLoadCSVColumns(t *testing.T) {
+	name := writeTestFile(t, "counters.csv", `Good,PlainValue,Colour,MoundedValue,MoundedGreenBird
+Hides,2,Red,4,TRUE`)
+	checkDataErrors(t, loadErrors(t, name), []DataError{
+		{Column: "Colour", Err: errText(`unknown column "Colour"`)},
+		{Err: errText(`missing column "PlainGreenBird"`)},
+	})
+}
+
+func TestLoadJSONErrors(t *testing.T) {
+	name := writeTestFile(t, "counters.json", `[
+	{"Good": "Hides", "PlainValue": 2, "MoundedValue": 4, "MoundedGreenBird": true},
+	{"Good": ["Mica", "Chert"], "PlainValue": 1, "MoundedValue": {}, "Colour": "Red"}
+]`)
+	checkDataErrors(t, loadErrors(t, name), []DataError{
+		{Row: 2, Column: "Colour", Err: errText(`unknown column "Colour"`)},
+		{Row: 2, Column: "MoundedValue", Err: errText("unsupported value")},
+		{Row: 2, Column: "MoundedValue", Err: errText("value is required")},
+		{Row: 2, Column: "Good", Err: errText("a counter has only one trade good")},
+	})
+}
+
+func TestLoadJSONMatchesCSV(t *testing.T) {
+	name := writeTestFile(t, "counters.json", `[
+	{"Good": "Hides", "PlainValue": 2, "MoundedValue": 4, "MoundedGreenBird": true}
+]`)
+	tb, err := loadTable("", "", name, counterColumns)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"Hides", "2", "", "4", "TRUE"}
+	if got := tb.rows[0]; strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("the JSON row reads as %q; want %q", got, want)
+	}
+}
+
+func TestLoadUnknownFileType(t *testing.T) {
+	name := writeTestFile(t, "counters.txt", "")
+	checkDataErrors(t, loadErrors(t, name), []DataError{
+		{Err: errText("unknown file type")},
+	})
+}
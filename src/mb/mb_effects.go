@@ -0,0 +1,283 @@
+package mb
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// EffectKind says what a card's Effect does.
+type EffectKind string
+
+const (
+	GainAPEffect        EffectKind = "GainAP"        // AP, IsWhite
+	ResourceBonusEffect EffectKind = "ResourceBonus" // Goods
+	SetModifierEffect   EffectKind = "SetModifier"   // Tribe, which may be All, and IsAscendant
+	AdvanceArmiesEffect EffectKind = "AdvanceArmies" // Tribes, in order of advance
+	RevoltEffect        EffectKind = "Revolt"        // Tribe
+	SpecialEventEffect  EffectKind = "SpecialEvent"  // Event
+)
+
+// Event names a card's special event.
+type Event string
+
+const (
+	AvariciaEvent       Event = "Avaricia" // the Spanish era begins
+	SpanishArrivalEvent Event = "Spanish"  // the Spanish army lands
+)
+
+// Effect is one thing a History Card does.  Which fields are used depends on
+// the kind of effect.
+type Effect struct {
+	Kind        EffectKind
+	AP          int         `json:",omitempty"`
+	IsWhite     bool        `json:",omitempty"` // the AP number is white
+	Goods       []TradeGood `json:",omitempty"`
+	Tribe       Tribe
+	Tribes      []Tribe `json:",omitempty"`
+	IsAscendant bool    `json:",omitempty"` // the modifier is +1, not -1
+	Event       Event   `json:",omitempty"`
+}
+
+func (e Effect) String() string {
+	switch e.Kind {
+	case GainAPEffect:
+		color := "black"
+		if e.IsWhite {
+			color = "white"
+		}
+		return fmt.Sprintf("%s AP number %d", color, e.AP)
+	case ResourceBonusEffect:
+		var goods []string
+		for _, g := range e.Goods {
+			goods = append(goods, g.String())
+		}
+		return "resource bonus for " + strings.Join(goods, " and ")
+	case SetModifierEffect:
+		return WarpathStatus{Warpath: e.Tribe, Modifier: e.modifier()}.String()
+	case AdvanceArmiesEffect:
+		var armies []string
+		for _, t := range e.Tribes {
+			armies = append(armies, t.String())
+		}
+		return "advance " + strings.Join(armies, ", ")
+	case RevoltEffect:
+		return e.Tribe.String() + " revolt"
+	case SpecialEventEffect:
+		return string(e.Event) + " event"
+	}
+	return string(e.Kind)
+}
+
+// copy returns a copy of the effect that shares no slices with it.
+func (e Effect) copy() Effect {
+	e.Goods = append([]TradeGood(nil), e.Goods...)
+	e.Tribes = append([]Tribe(nil), e.Tribes...)
+	return e
+}
+
+// modifier is the die roll modifier a SetModifier effect gives.
+func (e Effect) modifier() int {
+	if e.IsAscendant {
+		return 1
+	}
+	return -1
+}
+
+// cardPhase is the phase of the turn in which an effect happens.
+type cardPhase int
+
+const (
+	historyPhase cardPhase = iota // as soon as the card is drawn
+	economicPhase
+	hostilesPhase
+	revoltPhase
+)
+
+var effectPhases = map[EffectKind]cardPhase{
+	SpecialEventEffect:  historyPhase,
+	GainAPEffect:        economicPhase,
+	ResourceBonusEffect: economicPhase,
+	SetModifierEffect:   hostilesPhase,
+	AdvanceArmiesEffect: hostilesPhase,
+	RevoltEffect:        revoltPhase,
+}
+
+// effects returns the card's effects of the given kind, in order.
+func (c *HistoryCard) effects(k EffectKind) []Effect {
+	var found []Effect
+	for _, e := range c.Effects {
+		if e.Kind == k {
+			found = append(found, e)
+		}
+	}
+	return found
+}
+
+// hasEvent reports whether the card has the given special event.
+func (c *HistoryCard) hasEvent(ev Event) bool {
+	for _, e := range c.effects(SpecialEventEffect) {
+		if e.Event == ev {
+			return true
+		}
+	}
+	return false
+}
+
+// applyEffects carries out, in order, the current card's effects that
+// happen in the given phase.
+func (g *Game) applyEffects(p cardPhase) {
+	for _, e := range g.Board.Card.Effects {
+		if effectPhases[e.Kind] == p {
+			g.applyEffect(e)
+		}
+	}
+}
+
+func (g *Game) applyEffect(e Effect) {
+	switch e.Kind {
+	case SpecialEventEffect:
+		// each event has its own state; see stateHistoryPhase
+	case GainAPEffect:
+		g.gainAP(e)
+	case ResourceBonusEffect:
+		g.resourceBonus(e)
+	case SetModifierEffect:
+		g.Board.WarpathStatus = WarpathStatus{Warpath: e.Tribe, Modifier: e.modifier()}
+	case AdvanceArmiesEffect:
+		g.AdvancingArmies = append(g.AdvancingArmies, e.Tribes...)
+	case RevoltEffect:
+		g.RevoltingTribe = e.Tribe
+	}
+}
+
+// gainAP adds the APs for a card's AP number.  A white number gives that
+// many APs; a black one gives the trade goods less the number, but at
+// least 1.
+func (g *Game) gainAP(e Effect) {
+	b := g.Board.Economy
+	b.IsWhite, b.CardAP = e.IsWhite, e.AP
+	if e.IsWhite {
+		b.BaseAP = e.AP
+		g.logEvent("White AP number, APs added: %d", b.BaseAP)
+	} else {
+		b.BaseAP = b.TradeGoods - e.AP
+		if b.BaseAP <= 0 {
+			b.BaseAP = 1
+		}
+		g.logEvent("Black AP number: %d, trade goods: %d, APs added: %d", e.AP, b.TradeGoods, b.BaseAP)
+	}
+	b.TotalAP += b.BaseAP
+}
+
+// resourceBonus adds an AP for each controlled land producing one of the
+// bonus trade goods.
+func (g *Game) resourceBonus(e Effect) {
+	b := g.Board.Economy
+	for _, rb := range e.Goods {
+		bp := g.Board.controlledLandsWithGood(rb)
+		if bp > 0 {
+			g.logEvent("Resource bonus: %d AP for %s", bp, rb)
+			b.ResourceBonuses = append(b.ResourceBonuses, ResourceBonus{Good: rb, Lands: bp})
+			b.TotalAP += bp
+		}
+	}
+}
+
+// parseEffect reads an effect as it's written in the card data: the kind of
+// effect followed by its values, separated by spaces, such as
+// "GainAP 4 Black", "ResourceBonus Mica Seashells", "SetModifier All
+// Ascendant", "AdvanceArmies Caddo Caddo Natchez", "Revolt Cherokee" or
+// "SpecialEvent Avaricia".
+func parseEffect(s string) (Effect, error) {
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		return Effect{}, fmt.Errorf("empty effect")
+	}
+	e := Effect{Kind: EffectKind(fields[0])}
+	args := fields[1:]
+	count := func(min, max int) error {
+		if len(args) < min || max > 0 && len(args) > max {
+			return fmt.Errorf("wrong number of values for %s in %q", e.Kind, s)
+		}
+		return nil
+	}
+	var err error
+	switch e.Kind {
+	case GainAPEffect:
+		if err = count(2, 2); err != nil {
+			break
+		}
+		if e.AP, err = strconv.Atoi(args[0]); err != nil {
+			err = fmt.Errorf("%q is not a number", args[0])
+			break
+		}
+		e.IsWhite, err = choice(args[1], "White", "Black")
+	case ResourceBonusEffect:
+		if err = count(1, 0); err != nil {
+			break
+		}
+		for _, n := range args {
+			good, ok := tradeGoodNameLookup[n]
+			if !ok {
+				return e, fmt.Errorf("unknown trade good %q", n)
+			}
+			e.Goods = append(e.Goods, good)
+		}
+	case SetModifierEffect:
+		if err = count(2, 2); err != nil {
+			break
+		}
+		if e.Tribe, err = parseTribe(args[0]); err != nil {
+			break
+		}
+		e.IsAscendant, err = choice(args[1], "Ascendant", "Declining")
+	case AdvanceArmiesEffect:
+		if err = count(1, 0); err != nil {
+			break
+		}
+		for _, n := range args {
+			t, err := parseTribe(n)
+			if err != nil {
+				return e, err
+			}
+			e.Tribes = append(e.Tribes, t)
+		}
+	case RevoltEffect:
+		if err = count(1, 1); err == nil {
+			e.Tribe, err = parseTribe(args[0])
+		}
+	case SpecialEventEffect:
+		if err = count(1, 1); err != nil {
+			break
+		}
+		switch e.Event = Event(args[0]); e.Event {
+		case AvariciaEvent, SpanishArrivalEvent:
+		default:
+			err = fmt.Errorf("unknown event %q", args[0])
+		}
+	default:
+		err = fmt.Errorf("unknown effect %q", fields[0])
+	}
+	return e, err
+}
+
+func parseTribe(s string) (Tribe, error) {
+	t, ok := tribeNameLookup[s]
+	if !ok {
+		return t, fmt.Errorf("unknown tribe %q", s)
+	}
+	return t, nil
+}
+
+// choice reports whether s is yes rather than no, or returns an error if it
+// is neither.
+func choice(s, yes, no string) (bool, error) {
+	switch s {
+	case yes:
+		return true, nil
+	case no:
+		return false, nil
+	}
+	return false, fmt.Errorf("%q must be %s or %s", s, yes, no)
+}
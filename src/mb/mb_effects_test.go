@@ -0,0 +1,205 @@
+package mb
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseEffect(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Effect
+	}{
+		{"GainAP 4 White", Effect{Kind: GainAPEffect, AP: 4, IsWhite: true}},
+		{" GainAP  0 Black ", Effect{Kind: GainAPEffect}},
+		{"ResourceBonus Mica Seashells", Effect{Kind: ResourceBonusEffect, Goods: []TradeGood{Mica, Seashells}}},
+		{"SetModifier All Ascendant", Effect{Kind: SetModifierEffect, Tribe: All, IsAscendant: true}},
+		{"SetModifier Caddo Declining", Effect{Kind: SetModifierEffect, Tribe: Caddo}},
+		{"AdvanceArmies Caddo Caddo Spanish", Effect{Kind: AdvanceArmiesEffect, Tribes: []Tribe{Caddo, Caddo, SpanishTribe}}},
+		{"AdvanceArmies CaddoOrShawnee", Effect{Kind: AdvanceArmiesEffect, Tribes: []Tribe{CaddoOrShawnee}}},
+		{"Revolt Cherokee", Effect{Kind: RevoltEffect, Tribe: Cherokee}},
+		{"SpecialEvent Avaricia", Effect{Kind: SpecialEventEffect, Event: AvariciaEvent}},
+		{"SpecialEvent Spanish", Effect{Kind: SpecialEventEffect, Event: SpanishArrivalEvent}},
+	}
+	for _, tt := range tests {
+		got, err := parseEffect(tt.in)
+		if err != nil {
+			t.Errorf("parseEffect(%q): %v", tt.in, err)
+		} else if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseEffect(%q) = %+v; want %+v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseEffectErrors(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"", "empty effect"},
+		{"Gain 4 White", `unknown effect "Gain"`},
+		{"GainAP 4", "wrong number of values"},
+		{"GainAP four White", `"four" is not a number`},
+		{"GainAP 4 Grey", `"Grey" must be White or Black`},
+		{"ResourceBonus", "wrong number of values"},
+		{"ResourceBonus Mica Gold", `unknown trade good "Gold"`},
+		{"SetModifier Caddo", "wrong number of values"},
+		{"SetModifier Caddo Up", `"Up" must be Ascendant or Declining`},
+		{"AdvanceArmies Caddo Aztec", `unknown tribe "Aztec"`},
+		{"Revolt Caddo Natchez", "wrong number of values"},
+		{"SpecialEvent BlackBanner", `unknown event "BlackBanner"`},
+	}
+	for _, tt := range tests {
+		_, err := parseEffect(tt.in)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("parseEffect(%q) returned error %v; want %q", tt.in, err, tt.want)
+		}
+	}
+}
+
+func TestEffectsColumnErrors(t *testing.T) {
+	data := `Number,Title,Era,Effects
+1,Good,Hopewell,"GainAP 1 White, Revolt Caddo"
+2,Bad,Hopewell,"GainAP 1 White, Revolt Aztec, Advance Caddo"`
+	tb, err := readCSV("test", strings.NewReader(data), historyCardColumns)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cards, err := makeHistoryCards(tb)
+	errs, ok := err.(DataErrors)
+	if !ok || len(errs) != 2 {
+		t.Fatalf("loading the cards returned %v; want 2 errors", err)
+	}
+	for _, e := range errs {
+		if e.Row != 2 || e.Column != "Effects" {
+			t.Errorf("%v is for row %d, column %s; want row 2, column Effects", e, e.Row, e.Column)
+		}
+	}
+	if n := len(cards[1].Effects); n != 1 {
+		t.Errorf("the bad card has %d effects; want the 1 that could be read", n)
+	}
+}
+
+// testCard returns the card with the given number from the game's deck.
+func testCard(t *testing.T, g *Game, number int) *HistoryCard {
+	t.Helper()
+	for _, c := range g.HistoryDeck {
+		if c.Number == number {
+			return c
+		}
+	}
+	t.Fatalf("card %d isn't in the deck", number)
+	return nil
+}
+
+func TestCardEffects(t *testing.T) {
+	tests := []struct {
+		number int
+		want   []Effect
+	}{
+		{1, []Effect{ // Poverty Point
+			{Kind: GainAPEffect, AP: 1, IsWhite: true},
+			{Kind: SetModifierEffect, Tribe: Caddo},
+			{Kind: RevoltEffect, Tribe: Caddo},
+		}},
+		{19, []Effect{ // Moundville
+			{Kind: GainAPEffect, AP: 4, IsWhite: true},
+			{Kind: SetModifierEffect, Tribe: Natchez, IsAscendant: true},
+			{Kind: AdvanceArmiesEffect, Tribes: []Tribe{HoChunk, Natchez}},
+			{Kind: RevoltEffect, Tribe: Cherokee},
+		}},
+		{25, []Effect{ // Coosa
+			{Kind: SpecialEventEffect, Event: AvariciaEvent},
+			{Kind: GainAPEffect, AP: 4, IsWhite: true},
+			{Kind: SetModifierEffect, Tribe: Cherokee, IsAscendant: true},
+			{Kind: AdvanceArmiesEffect, Tribes: []Tribe{Cherokee, Shawnee, Natchez}},
+		}},
+		{26, []Effect{ // The Spanish
+			{Kind: SpecialEventEffect, Event: SpanishArrivalEvent},
+			{Kind: GainAPEffect, AP: 0, IsWhite: true},
+			{Kind: AdvanceArmiesEffect, Tribes: []Tribe{HoChunk, Shawnee, SpanishTribe}},
+		}},
+		{27, []Effect{ // Chalcedony & Obsidian
+			{Kind: GainAPEffect, AP: 4},
+			{Kind: ResourceBonusEffect, Goods: []TradeGood{Chalcedony, Obsidian}},
+			{Kind: AdvanceArmiesEffect, Tribes: []Tribe{Caddo, Caddo, Natchez}},
+		}},
+		{37, []Effect{ // The Chunkey Game
+			{Kind: GainAPEffect, AP: 5},
+			{Kind: AdvanceArmiesEffect, Tribes: []Tribe{CaddoOrShawnee}},
+		}},
+		{44, []Effect{ // Pottery
+			{Kind: GainAPEffect, AP: 3},
+			{Kind: SetModifierEffect, Tribe: All, IsAscendant: true},
+			{Kind: AdvanceArmiesEffect, Tribes: []Tribe{Natchez, Cherokee}},
+			{Kind: RevoltEffect, Tribe: Shawnee},
+		}},
+	}
+	g := newTestGame(t, GameOptions{})
+	for _, tt := range tests {
+		c := testCard(t, g, tt.number)
+		if !reflect.DeepEqual(c.Effects, tt.want) {
+			t.Errorf("card %s has effects %+v; want %+v", c, c.Effects, tt.want)
+		}
+	}
+}
+
+func TestCardsInPlay(t *testing.T) {
+	tests := []struct {
+		number  int
+		ap      int
+		status  WarpathStatus
+		armies  []Tribe
+		revolt  Tribe
+		bonuses int
+	}{
+		{1, 1, WarpathStatus{Warpath: Caddo, Modifier: -1}, nil, Caddo, 0},
+		{7, 3, WarpathStatus{Warpath: HoChunk, Modifier: 1}, nil, HoChunk, 0},
+		{17, 4, WarpathStatus{Warpath: Cherokee, Modifier: -1}, []Tribe{Caddo}, HoChunk, 0},
+		{36, 1, WarpathStatus{Warpath: None}, []Tribe{Natchez, Shawnee}, Cherokee, 0},
+		{44, 1, WarpathStatus{Warpath: All, Modifier: 1}, []Tribe{Natchez, Cherokee}, Shawnee, 0},
+		{49, 1, WarpathStatus{Warpath: All, Modifier: -1}, []Tribe{Cherokee, Caddo}, None, 0},
+	}
+	for _, tt := range tests {
+		g := newTestGame(t, GameOptions{})
+		g.Board.Card = testCard(t, g, tt.number)
+		ap := g.Board.ActionPoints
+		stateEconomicPhase{}.handle(g)
+		if got := g.Board.ActionPoints - ap; got != tt.ap {
+			t.Errorf("card %s: gained %d APs; want %d", g.Board.Card, got, tt.ap)
+		}
+		if n := len(g.Board.Economy.ResourceBonuses); n != tt.bonuses {
+			t.Errorf("card %s: %d resource bonuses; want %d", g.Board.Card, n, tt.bonuses)
+		}
+		stateHostilesPhase{}.handle(g)
+		if g.Board.WarpathStatus != tt.status {
+			t.Errorf("card %s: warpath status %+v; want %+v", g.Board.Card, g.Board.WarpathStatus, tt.status)
+		}
+		if !reflect.DeepEqual(g.AdvancingArmies, tt.armies) {
+			t.Errorf("card %s: advancing armies %v; want %v", g.Board.Card, g.AdvancingArmies, tt.armies)
+		}
+		g.applyEffects(revoltPhase)
+		if g.RevoltingTribe != tt.revolt {
+			t.Errorf("card %s: %s revolts; want %s", g.Board.Card, g.RevoltingTribe, tt.revolt)
+		}
+	}
+}
+
+func TestCardEvents(t *testing.T) {
+	tests := []struct {
+		number int
+		want   state
+	}{
+		{24, stateEconomicPhase{}},
+		{25, stateBlackBannerEvent{}},
+		{26, stateSpanishEvent{}},
+	}
+	for _, tt := range tests {
+		g := newTestGame(t, GameOptions{})
+		c := testCard(t, g, tt.number)
+		g.HistoryDeck = Pile{c}
+		if s := (stateHistoryPhase{}).handle(g); s != tt.want {
+			t.Errorf("drawing card %s goes to %T; want %T", c, s, tt.want)
+		}
+	}
+}
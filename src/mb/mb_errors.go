@@ -0,0 +1,172 @@
+package mb
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ErrorCode identifies the rule a request broke.  Codes are errors, so
+// errors.Is(err, ErrInsufficientAP) reports whether err is a RuleError with
+// that code.
+type ErrorCode string
+
+func (c ErrorCode) Error() string {
+	return string(c)
+}
+
+const (
+	ErrSyntax           ErrorCode = "Syntax"           // the command can't be read
+	ErrUnknownAction    ErrorCode = "UnknownAction"    // the action isn't one of Actions()
+	ErrMissingTarget    ErrorCode = "MissingTarget"    // the action needs a target
+	ErrWrongTarget      ErrorCode = "WrongTarget"      // the target is the wrong kind of thing
+	ErrTargetNotFound   ErrorCode = "TargetNotFound"   // nothing matches the target
+	ErrAmbiguousTarget  ErrorCode = "AmbiguousTarget"  // more than one thing matches the target
+	ErrInsufficientAP   ErrorCode = "InsufficientAP"   // the action costs more APs than are left
+	ErrWrongEra         ErrorCode = "WrongEra"         // the action isn't allowed in this era
+	ErrNoChiefdom       ErrorCode = "NoChiefdom"       // the land has no chiefdom
+	ErrWilderness       ErrorCode = "Wilderness"       // the land is wilderness
+	ErrNotControlled    ErrorCode = "NotControlled"    // the player doesn't control the chiefdom
+	ErrAlreadyMounded   ErrorCode = "AlreadyMounded"   // the chiefdom already has a mound
+	ErrBlockedPeacePipe ErrorCode = "BlockedPeacePipe" // the next chiefdom must be incorporated first
+	ErrEndOfWarpath     ErrorCode = "EndOfWarpath"     // the peace pipe can't go any further
+	ErrOccupied         ErrorCode = "Occupied"         // a hostile army occupies the chiefdom
+	ErrNotImplemented   ErrorCode = "NotImplemented"   // the game doesn't support the action yet
+)
+
+// RuleError is a request that breaks the rules of the game.  Besides the
+// message it carries a code and whichever of the tribe, land, input and
+// costs it's about, so a front end can explain it in its own words.
+type RuleError struct {
+	Code        ErrorCode
+	Message     string
+	Tribe       string   `json:",omitempty"`
+	Land        string   `json:",omitempty"`
+	Era         string   `json:",omitempty"` // the era the action needs
+	Input       string   `json:",omitempty"` // the text that didn't match
+	Matches     []string `json:",omitempty"` // what an ambiguous input matched
+	Suggestions []string `json:",omitempty"` // what a mistyped input might have meant
+	Cost        int      `json:",omitempty"`
+	Available   int      `json:",omitempty"`
+}
+
+func (e *RuleError) Error() string {
+	return e.Message
+}
+
+// Is reports whether target is e's code.
+func (e *RuleError) Is(target error) bool {
+	return target == e.Code
+}
+
+func ruleError(code ErrorCode, f string, args ...interface{}) *RuleError {
+	return &RuleError{Code: code, Message: fmt.Sprintf(f, args...)}
+}
+
+// suggesting adds the suggestions, if there are any, to the error and its
+// message.
+func (e *RuleError) suggesting(suggestions []string) *RuleError {
+	if len(suggestions) == 0 {
+		return e
+	}
+	e.Suggestions = suggestions
+	quoted := make([]string, len(suggestions))
+	for i, s := range suggestions {
+		quoted[i] = fmt.Sprintf("%q", s)
+	}
+	e.Message += fmt.Sprintf(" Did you mean %s?", strings.Join(quoted, " or "))
+	return e
+}
+
+func errSyntax(input string, f string, args ...interface{}) error {
+	e := ruleError(ErrSyntax, f, args...)
+	e.Input = input
+	return e
+}
+
+func errUnknownAction(input string, suggestions []string) error {
+	e := ruleError(ErrUnknownAction, "Unknown action: %q.", input)
+	e.Input = input
+	return e.suggesting(suggestions)
+}
+
+func errMissingTarget(as *ActionSpec) error {
+	return ruleError(ErrMissingTarget, "The %s action requires a target.", as.Description)
+}
+
+func errWrongTarget(as *ActionSpec, kind string) error {
+	return ruleError(ErrWrongTarget, "The %s action must be aimed at a %s.", as.Description, kind)
+}
+
+func errTargetNotFound(kind, input string, suggestions []string) error {
+	e := ruleError(ErrTargetNotFound, "%q doesn't match %s.", input, kind)
+	e.Input = input
+	return e.suggesting(suggestions)
+}
+
+func errAmbiguousTarget(kind, input string, matches []string) error {
+	e := ruleError(ErrAmbiguousTarget, "%q matches more than one %s.", input, kind)
+	e.Input = input
+	e.Matches = matches
+	return e
+}
+
+func errInsufficientAP(cost, available int) error {
+	e := ruleError(ErrInsufficientAP, "This action costs %d APs, but you only have %d.", cost, available)
+	e.Cost = cost
+	e.Available = available
+	return e
+}
+
+func errWrongEra(era Era) error {
+	e := ruleError(ErrWrongEra, "This action is only allowed during the %s era.", era)
+	e.Era = era.String()
+	return e
+}
+
+func errNoChiefdom(l Land) error {
+	e := ruleError(ErrNoChiefdom, "%s does not contain a chiefdom.", l.Name)
+	e.Land = l.Name
+	return e
+}
+
+func errWilderness(l Land) error {
+	e := ruleError(ErrWilderness, "%s cannot contain a chiefdom.", l.Name)
+	e.Land = l.Name
+	return e
+}
+
+func errNotControlled(l Land) error {
+	e := ruleError(ErrNotControlled, "You do not control %s yet.", l.Name)
+	e.Land = l.Name
+	return e
+}
+
+func errAlreadyMounded(l Land) error {
+	e := ruleError(ErrAlreadyMounded, "%s is already mounded.", l.Name)
+	e.Land = l.Name
+	return e
+}
+
+func errBlockedPeacePipe(t Tribe, l Land) error {
+	e := ruleError(ErrBlockedPeacePipe, "Cannot advance Peace Pipe; chiefdom in %s must be incorporated first.", l.Name)
+	e.Tribe = t.String()
+	e.Land = l.Name
+	return e
+}
+
+func errEndOfWarpath(t Tribe, l Land) error {
+	e := ruleError(ErrEndOfWarpath, "Peace Pipe on %s cannot be advanced.", l.Name)
+	e.Tribe = t.String()
+	e.Land = l.Name
+	return e
+}
+
+func errOccupied(l Land) error {
+	e := ruleError(ErrOccupied, "%s is occupied by a hostile army.", l.Name)
+	e.Land = l.Name
+	return e
+}
+
+func errNotImplemented(as *ActionSpec) error {
+	return ruleError(ErrNotImplemented, "The %s action is not available in this version of the game.", as.Description)
+}
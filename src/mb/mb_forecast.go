@@ -0,0 +1,96 @@
+package mb
+
+import "math/rand"
+
+// The turns and number of deck shuffles Forecast uses when it isn't told
+// otherwise.
+const (
+	DefaultForecastTurns    = 3
+	DefaultForecastRollouts = 200
+)
+
+// Threat is the forecast for one hostile army.
+type Threat struct {
+	Tribe    Tribe
+	Space    int     // the army's space now; 0 if it isn't on the board
+	Advances float64 // how many times the army is expected to advance
+	Reach    float64 // chance the army advances from space 1 to Cahokia
+	// TODO:  the chance of breaching the palisade, once armies attack Cahokia
+}
+
+// Forecast gives the chances of the hostile armies reaching Cahokia over
+// the next few turns.
+type Forecast struct {
+	Turns    int
+	Rollouts int
+	Threats  []Threat // the five tribes, then the Spanish
+}
+
+// Forecast estimates, for each army on the board, how often the History
+// Cards of the next few turns will advance it and the chance of them
+// advancing it into Cahokia.  It draws the cards from copies of the deck
+// reshuffled as far as the player can tell, so the forecast doesn't depend
+// on hidden information, and takes a card that advances Caddo or Shawnee to
+// advance whichever of the two is nearer Cahokia by then, as the game does.  Attacks on Cahokia aren't part of
+// the game yet, so there's no forecast of the palisade being breached.
+func (g *Game) Forecast(turns, rollouts int, seed int64) *Forecast {
+	if turns < 1 {
+		turns = DefaultForecastTurns
+	}
+	if rollouts < 1 {
+		rollouts = DefaultForecastRollouts
+	}
+	f := &Forecast{Turns: turns, Rollouts: rollouts}
+	index := make(map[Tribe]int)
+	for i, t := range append(append([]Tribe(nil), tribes...), SpanishTribe) {
+		th := Threat{Tribe: t}
+		if h := g.Board.findHostile(t); h != nil {
+			_, th.Space = fromLandIndex(h.LandIndex)
+		}
+		f.Threats = append(f.Threats, th)
+		index[t] = i
+	}
+
+	r := rand.New(rand.NewSource(seed))
+	w := 1 / float64(rollouts)
+	for i := 0; i < rollouts; i++ {
+		advances := make(map[Tribe]int)
+		deck := g.shuffledDeck(r)
+		for n := 0; n < turns && n < len(deck); n++ {
+			for _, e := range deck[n].effects(AdvanceArmiesEffect) {
+				for _, t := range e.Tribes {
+					if t == CaddoOrShawnee {
+						t = caddoOrShawnee(
+							f.spaceAfter(Caddo, advances[Caddo], index),
+							f.spaceAfter(Shawnee, advances[Shawnee], index))
+					}
+					advances[t]++
+				}
+			}
+		}
+		for t, n := range advances {
+			th := &f.Threats[index[t]]
+			if th.Space == 0 {
+				continue
+			}
+			th.Advances += w * float64(n)
+			if n >= th.Space {
+				th.Reach += w
+			}
+		}
+	}
+	return f
+}
+
+// spaceAfter returns the space a tribe's army will be on after the given
+// number of advances.  An army stops at the gates of Cahokia, on space 1.
+func (f *Forecast) spaceAfter(t Tribe, advances int, index map[Tribe]int) int {
+	n := f.Threats[index[t]].Space
+	if n == 0 {
+		return 0
+	}
+	if n -= advances; n < 1 {
+		n = 1
+	}
+	return n
+}
@@ -0,0 +1,52 @@
+package mb
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestForecastLeavesGameAlone(t *testing.T) {
+	g := startedTestGame(t)
+	want := snapshot(t, g)
+	f := g.Forecast(0, 0, 1)
+	if snapshot(t, g) != want {
+		t.Error("forecasting changed the game")
+	}
+	if f.Turns != DefaultForecastTurns || f.Rollouts != DefaultForecastRollouts {
+		t.Errorf("forecast for %d turns with %d rollouts; want the defaults", f.Turns, f.Rollouts)
+	}
+	if again := g.Forecast(0, 0, 1); !reflect.DeepEqual(f, again) {
+		t.Errorf("forecasts with the same seed differ: %+v and %+v", f, again)
+	}
+}
+
+func TestForecastWholeDeck(t *testing.T) {
+	g := startedTestGame(t)
+	h := g.Board.findHostile(Cherokee)
+	h.LandIndex = toLandIndex(Cherokee, 2)
+	advances := 0
+	for _, c := range g.HistoryDeck {
+		for _, e := range c.effects(AdvanceArmiesEffect) {
+			for _, tr := range e.Tribes {
+				if tr == Cherokee {
+					advances++
+				}
+			}
+		}
+	}
+
+	// with every card drawn, the shuffle makes no difference
+	f := g.Forecast(len(g.HistoryDeck), 10, 1)
+	for _, th := range f.Threats {
+		switch th.Tribe {
+		case Cherokee:
+			if th.Space != 2 || int(th.Advances+0.5) != advances || th.Reach < 0.999 {
+				t.Errorf("Cherokee threat is %+v; want space 2, %d advances and a reach of 1", th, advances)
+			}
+		case SpanishTribe:
+			if th.Space != 0 || th.Advances != 0 || th.Reach != 0 {
+				t.Errorf("the Spanish aren't on the board, but their threat is %+v", th)
+			}
+		}
+	}
+}
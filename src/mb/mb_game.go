@@ -7,17 +7,23 @@ import (
 	"time"
 )
 
-// important package-wide variables go here
-var (
-	rng *rand.Rand
-)
-
-func init() {
-	rng = rand.New(rand.NewSource(time.Now().Unix()))
-}
-
-func die() int {
-	return rng.Intn(6) + 1
+// die rolls a die using the game's random number generator.
+func (g *Game) die() int {
+	return g.rng.Intn(6) + 1
+}
+
+// modified returns a value on a tribe's warpath with the warpath status
+// modifier applied, kept between 1 and 6, logging the change if there is a
+// modifier: +1 for an ascendant tribe, -1 for a declining one.  For now only
+// the chiefdom value an incorporation roll must beat is modified.
+func (g *Game) modified(t Tribe, what string, v int) int {
+	m := g.Board.WarpathStatus.modifierFor(t)
+	if m == 0 {
+		return v
+	}
+	mv := normalizeDie(v + m)
+	g.logEvent("%s status modifies %s from %d to %d.", g.Board.WarpathStatus, what, v, mv)
+	return mv
 }
 
 func normalizeDie(d int) int {
@@ -43,7 +49,18 @@ type Game struct {
 	Action          *Action
 	Error           error
 	LogToConsole    bool
+	Debug           bool // check the board after every state; see checkInvariants
+	Rollback        bool // copy the game before each request, to roll back to if it panics
 	Log 			[]string
+	Options         GameOptions
+	DeckSections    []DeckSection
+	EndCause        string // what ended the game
+	EndCard         int    // the History Card in play when the game ended; 0 if none
+	EndRevolt       Tribe  // the tribe that revolted on the last turn, or None
+	Won             bool
+	Requests        []Request // every request handled, for replaying the game
+	src             *source
+	rng             *rand.Rand
 }
 
 type Request struct {
@@ -55,13 +72,29 @@ type Response struct {
 	Error  error
 }
 
-// NewGame initializes a new Game.
-func NewGame() *Game {
-	return &Game{
-		HistoryDeck: makeHistoryDeck(),
-		Board:       makeBoard(),
-		Cup:         makeCup(),
+// NewGame initializes a new Game with the given options.  Unset options
+// take their values from the Normal preset.
+func NewGame(o GameOptions) (*Game, error) {
+	o, err := o.resolve()
+	if err != nil {
+		return nil, err
+	}
+	d, err := loadGameData(o)
+	if err != nil {
+		return nil, err
+	}
+	if o.Seed == 0 {
+		o.Seed = time.Now().UnixNano()
 	}
+	g := &Game{
+		Board:          makeBoard(o, d.Lands),
+		Options:        o,
+		RevoltingTribe: None,
+	}
+	g.seed(o.Seed)
+	g.Cup = makeCup(d.Counters, g.rng)
+	g.HistoryDeck, g.DeckSections = makeHistoryDeck(d.Cards, *o.EarlyHopewellCards, g.rng)
+	return g, nil
 }
 
 func (g *Game) StartGame() {
@@ -69,8 +102,19 @@ func (g *Game) StartGame() {
 	g.HandleRequest(Request{})
 }
 
-// HandleRequest handles the next request pending for the game.
+// HandleRequest handles the next request pending for the game.  If that
+// panics, the game is rolled back and the Response holds an InternalError.
 func (g *Game) HandleRequest(q Request) {
+	var saved *Game
+	if g.Rollback {
+		saved = g.Clone()
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			g.recoverFrom(p, saved, q)
+		}
+	}()
+	g.Requests = append(g.Requests, q)
 	g.Request = q
 	g.Response = nil
 	g.Error = nil
@@ -79,7 +123,11 @@ func (g *Game) HandleRequest(q Request) {
 		case stateEndProgram:
 			return
 		default:
-			g.State = g.State.handle(g)
+			s := g.State
+			g.State = s.handle(g)
+			if g.Debug {
+				g.checkInvariants(s)
+			}
 			// if there's a response set, send it and wait for the next request.
 			if g.Response != nil {
 				g.Board.WarpathActions = g.availableWarpathActions()
@@ -91,14 +139,35 @@ func (g *Game) HandleRequest(q Request) {
 }
 
 // drawChiefdomCounter draws the next ChiefdomCounter from the cup and positions it
-// in the given Land.
-func (g *Game) drawChiefdomCounter(l Land) {
+// in the given Land.  If the cup is empty, or the land already has a chiefdom,
+// nothing is drawn; it reports whether a chiefdom was placed.
+func (g *Game) drawChiefdomCounter(l Land) bool {
+	if g.Board.Chiefdoms[l.Index] != nil {
+		return false
+	}
 	var c *ChiefdomCounter
 	c, g.Cup = drawFromCup(g.Cup)
+	if c == nil {
+		g.logEvent("The cup is empty; no chiefdom is found in %s.", l.Name)
+		return false
+	}
 	g.Board.Chiefdoms[l.Index] = &Chiefdom{
 		Counter:   c,
 		LandIndex: l.Index,
 	}
+	return true
+}
+
+// removeChiefdom takes the chiefdom off a land and returns its counter to the
+// cup.
+func (g *Game) removeChiefdom(l Land) {
+	c := g.Board.Chiefdoms[l.Index]
+	if c == nil {
+		return
+	}
+	g.Board.Chiefdoms[l.Index] = nil
+	g.returnToCup(c.Counter)
+	g.logEvent("The chiefdom (%s) in %s is lost; its counter goes back in the cup.", c, l.Name)
 }
 
 // drawHistoryCard draws the next HistoryCard from the deck.  If Board.Card is nil, game over.
@@ -108,6 +177,59 @@ func (g *Game) drawHistoryCard() {
 	g.Board.Card = c
 }
 
+// IsOver reports whether the game has ended.
+func (g *Game) IsOver() bool {
+	_, over := g.State.(stateEndProgram)
+	return over
+}
+
+// Score totals the values of the chiefdoms the player controls, leaving out
+// any occupied by a hostile army.
+func (g *Game) Score() int {
+	score := 0
+	for _, c := range g.Board.Chiefdoms {
+		if g.Board.controls(c) {
+			score += c.getValue()
+		}
+	}
+	return score
+}
+
+// Result describes how a game came out.
+type Result struct {
+	Won    bool
+	Score  int
+	Turns  int
+	Cause  string
+	Card   int   // the History Card in play at the end; 0 if none
+	Revolt Tribe // the tribe that revolted on the last turn, or None
+}
+
+// Result reports how the game came out, or how it stands if it isn't over.
+func (g *Game) Result() Result {
+	r := Result{
+		Won:    g.Won,
+		Score:  g.Score(),
+		Turns:  g.Board.Turn,
+		Cause:  g.EndCause,
+		Card:   g.EndCard,
+		Revolt: g.EndRevolt,
+	}
+	if !g.IsOver() {
+		r.Card, r.Revolt = g.cardInPlay()
+	}
+	return r
+}
+
+// cardInPlay returns the number of the History Card in play and the tribe
+// it has revolting, or 0 and None if there's no card.
+func (g *Game) cardInPlay() (int, Tribe) {
+	if g.Board.Card == nil {
+		return 0, None
+	}
+	return g.Board.Card.Number, g.RevoltingTribe
+}
+
 func (g *Game) logPhase(f string, args ...interface{}) {
 	g.log("\n\n"+f, args...)
 }
@@ -142,45 +264,22 @@ func (stateBlackBannerEvent) handle(*Game) state {
 type stateEconomicPhase struct{}
 
 func (stateEconomicPhase) handle(g *Game) state {
-
-	landsWithGood := func(t TradeGood) int {
-		var result int
-		for i := 0; i < LandCount; i++ {
-			c := g.Board.Chiefdoms[i]
-			if c != nil && c.Counter.Good == t {
-				result += 1
-			}
-		}
-		return result
-	}
-
 	g.logPhase("Economic Phase:")
-	c := g.Board.Card
-	if c.IsWhite {
-		g.Board.ActionPoints += c.ActionPoints
-		g.logEvent("White AP number, APs added: %d", c.ActionPoints)
-	} else {
-		ap := g.Board.TradeGoods - c.ActionPoints
-		if ap <= 0 {
-			ap = 1
-		}
-		g.logEvent("Black AP number: %d, trade goods: %d, APs added: %d")
-		for _, rb := range c.ResourceBonus {
-			bp := landsWithGood(rb)
-			if bp > 0 {
-				g.logEvent("Resource bonus: %d AP for %s", bp, rb)
-				ap += bp
-			}
-		}
-		g.Board.ActionPoints += ap
-		g.logEvent("Total APs added: %d", ap)
-	}
+	e := &EconomicBreakdown{Card: g.Board.Card.Number}
+	g.Board.TradeGoods = g.Board.controlledTradeGoods()
+	e.TradeGoods = g.Board.TradeGoods
+	g.logEvent("Trade goods earned from controlled chiefdoms: %d", e.TradeGoods)
+	g.Board.Economy = e
+	g.applyEffects(economicPhase)
+	g.Board.ActionPoints += e.TotalAP
+	g.logEvent("Total APs added: %d", e.TotalAP)
 	return stateHostilesPhase{}
 }
 
 type stateEndOfGame struct{}
 
 func (stateEndOfGame) handle(g *Game) state {
+	g.EndCard, g.EndRevolt = g.cardInPlay()
 	return stateEndProgram{}
 }
 
@@ -197,12 +296,14 @@ func (stateHistoryPhase) handle(g *Game) state {
 	g.drawHistoryCard()
 	g.logEvent("Drew %s", g.Board.Card)
 	if g.Board.Card == nil {
+		g.EndCause = "The History Deck ran out."
+		g.Won = true
 		return stateEndOfGame{}
 	}
-	if g.Board.Card.IsAvaricia {
+	if g.Board.Card.hasEvent(AvariciaEvent) {
 		return stateBlackBannerEvent{}
 	}
-	if g.Board.Card.IsSpanish {
+	if g.Board.Card.hasEvent(SpanishArrivalEvent) {
 		return stateSpanishEvent{}
 	}
 	return stateEconomicPhase{}
@@ -212,24 +313,12 @@ type stateHostilesPhase struct{}
 
 func (stateHostilesPhase) handle(g *Game) state {
 	g.logPhase("Hostiles Phase:")
-	c := g.Board.Card
-	drm := 0
-	if c.Modifier != None {
-		if c.IsAscendant {
-			drm = 1
-		} else {
-			drm = -1
-		}
-	}
-	g.Board.WarpathStatus = WarpathStatus{Warpath: g.Board.Card.Modifier, Modifier: drm}
+	g.Board.WarpathStatus = WarpathStatus{Warpath: None}
+	g.AdvancingArmies = nil
+	g.RevoltingTribe = None
+	g.applyEffects(hostilesPhase)
 	g.logEvent("Warpath status is %s", g.Board.WarpathStatus)
-
-	g.AdvancingArmies = make([]Tribe, len(c.AdvancingArmies))
-	copy(g.AdvancingArmies, c.AdvancingArmies)
-	g.RevoltingTribe = c.Revolt
-
 	return stateAdvanceHostile{}
-
 }
 
 type stateAdvanceHostile struct{}
@@ -239,23 +328,60 @@ func (stateAdvanceHostile) handle(g *Game) state {
 		g.logEvent("No advancing armies.")
 		return stateRevoltPhase{}
 	}
-	// TODO:  once we can get out of Hopewell
-	//a := g.AdvancingArmies[0]
+	a := g.AdvancingArmies[0]
 	g.AdvancingArmies = g.AdvancingArmies[1:]
-	//h := g.Board.Hostiles[int(a)]
+	h := g.Board.findHostile(a)
+	switch {
+	case a == CaddoOrShawnee:
+		t := caddoOrShawnee(g.Board.hostileSpace(Caddo), g.Board.hostileSpace(Shawnee))
+		if h := g.Board.findHostile(t); h != nil {
+			g.logEvent("The %s army is nearer Cahokia, so it advances.", t)
+			g.advanceHostile(h)
+		}
+	case h == nil:
+		g.logEvent("The %s army is not on the board.", a)
+	default:
+		g.advanceHostile(h)
+	}
 	return stateAdvanceHostile{}
 }
 
+// caddoOrShawnee returns the army that advances for a card that advances
+// the Caddo or Shawnee army, given the spaces they're on: the one nearer
+// Cahokia, or Caddo if they're level.  A space of 0 means the army isn't on
+// the board.
+func caddoOrShawnee(caddo, shawnee int) Tribe {
+	if shawnee != 0 && (caddo == 0 || shawnee < caddo) {
+		return Shawnee
+	}
+	return Caddo
+}
+
+// advanceHostile moves a hostile army one space towards Cahokia, occupying
+// any chiefdom the player holds in the land it moves to.
+func (g *Game) advanceHostile(h *HostileMarker) {
+	if _, n := fromLandIndex(h.LandIndex); n == 1 {
+		g.logEvent("The %s army is at the gates of Cahokia.", h.Tribe)
+		// TODO:  attack Cahokia
+		return
+	}
+	g.Board.moveHostile(h, -1)
+	g.logEvent("The %s army advances to %s.", h.Tribe, g.Board.Lands[h.LandIndex].Name)
+	g.logOccupation(h)
+}
+
 type stateRevoltPhase struct{}
 
 func (stateRevoltPhase) handle(g *Game) (s state) {
 	s = stateActionPhase{}
+	g.applyEffects(revoltPhase)
 	tribe := g.RevoltingTribe
 	if tribe == None {
 		return
 	}
 	g.logEvent("%s tribe is revolting.", tribe)
-	roll := die()
+	// TODO:  apply the warpath status modifier to the roll with modified
+	roll := g.die()
 	land := g.Board.findLand(tribe, roll)
 	g.logEvent("%d rolled, land = %s", roll, land)
 	if land.IsWilderness {
@@ -288,6 +414,22 @@ func (stateRevoltPhase) handle(g *Game) (s state) {
 	return
 }
 
+// retreatPeacePipe moves a tribe's peace pipe back a space if it's on the
+// given land, taking it off the board if it's in space 1.
+func (g *Game) retreatPeacePipe(t Tribe, l Land) {
+	if !g.Board.PeacePipes[l.Index] {
+		return
+	}
+	g.Board.PeacePipes[l.Index] = false
+	if l.Space == 1 {
+		g.logEvent("Removed the %s Peace Pipe from %s.", t, l)
+		return
+	}
+	prev := g.Board.Lands[l.Index-1]
+	g.Board.PeacePipes[prev.Index] = true
+	g.logEvent("Retreated the %s Peace Pipe from %s to %s.", t, l, prev)
+}
+
 type stateActionPhase struct{}
 
 func (stateActionPhase) handle(g *Game) state {
@@ -326,18 +468,9 @@ func (stateProcessAction) handle(g *Game) state {
 // It returns an error if the action is invalid for any reason.
 func (g *Game) prepareAction() error {
 	a := g.Action
-	// can we afford the action?
-	var cost int
-	switch a.Spec.Cost {
-	case ZeroCost, OneCost, TwoCost:
-		cost = int(a.Spec.Cost)
-	case ChiefdomValueCost:
-		// TODO
-	case PalisadeValueCost:
-		cost = g.Board.palisade().Value
-	}
-	if avail := g.Board.ActionPoints; cost > avail {
-		return fmt.Errorf("This action costs %d APs, but you only have %d.", cost, avail)
+	cost, err := g.checkCost(a.Spec, a.Target)
+	if err != nil {
+		return err
 	}
 	a.ActualCost = cost
 	return nil
@@ -349,12 +482,42 @@ func (stateEndOfTurnPhase) handle(g *Game) state {
 	g.logPhase("End of Turn Phase:")
 	// remove markers
 	// remove enemy-held chiefdoms
+	g.resolveOccupations()
 	// degrade chiefdoms
 	// reset trade goods marker
+	g.Board.TradeGoods = 0
 	// deploy great sun
 	return stateStartOfTurn{}
 }
 
+// logOccupation logs a hostile army occupying the chiefdom on its land, if
+// it's one the player holds.
+func (g *Game) logOccupation(h *HostileMarker) {
+	l := g.Board.Lands[h.LandIndex]
+	if c := g.Board.Chiefdoms[l.Index]; c != nil && (c.IsControlled || c.IsMounded) {
+		g.logEvent("The %s army occupies the chiefdom in %s; it earns nothing while occupied.", h.Tribe, l.Name)
+	}
+}
+
+// resolveOccupations deals with the chiefdoms hostile armies occupy at the
+// end of the turn.  A mounded chiefdom loses its mound; a plain one is lost,
+// its counter going back to the cup and any peace pipe on it retreating.
+func (g *Game) resolveOccupations() {
+	for i, c := range g.Board.Chiefdoms {
+		if c == nil || !(c.IsControlled || c.IsMounded) || !g.Board.isOccupied(i) {
+			continue
+		}
+		l := g.Board.Lands[i]
+		if c.IsMounded {
+			c.IsMounded = false
+			g.logEvent("The occupying army destroys the mound in %s.", l.Name)
+			continue
+		}
+		g.removeChiefdom(l)
+		g.retreatPeacePipe(l.Warpath, l)
+	}
+}
+
 type stateTest struct{}
 
 func (stateTest) handle(g *Game) state {
@@ -376,8 +539,9 @@ func (stateStartOfGame) handle(g *Game) state {
 
 	for t := HoChunk; t <= Caddo; t++ {
 		land := g.Board.Lands[toLandIndex(t, 1)]
-		g.drawChiefdomCounter(land)
-		g.logEvent("Land %s: %s", land, g.Board.Chiefdoms[land.Index])
+		if g.drawChiefdomCounter(land) {
+			g.logEvent("Land %s: %s", land, g.Board.Chiefdoms[land.Index])
+		}
 	}
 
 	return stateStartOfTurn{}
@@ -386,5 +550,6 @@ func (stateStartOfGame) handle(g *Game) state {
 type stateStartOfTurn struct{}
 
 func (stateStartOfTurn) handle(g *Game) state {
+	g.Board.Turn++
 	return stateHistoryPhase{}
 }
@@ -0,0 +1,90 @@
+package mb
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestModified(t *testing.T) {
+	tests := []struct {
+		status WarpathStatus
+		v      int
+		want   int
+		log    string
+	}{
+		{WarpathStatus{Warpath: None}, 3, 3, ""},
+		{WarpathStatus{Warpath: Caddo, Modifier: 1}, 3, 3, ""},
+		{WarpathStatus{Warpath: HoChunk, Modifier: 1}, 3, 4, "from 3 to 4"},
+		{WarpathStatus{Warpath: All, Modifier: -1}, 3, 2, "from 3 to 2"},
+		{WarpathStatus{Warpath: All, Modifier: -1}, 1, 1, "from 1 to 1"},
+		{WarpathStatus{Warpath: HoChunk, Modifier: 1}, 6, 6, "from 6 to 6"},
+	}
+	for _, tt := range tests {
+		g := newTestGame(t, GameOptions{})
+		g.Board.WarpathStatus = tt.status
+		if got := g.modified(HoChunk, "the roll", tt.v); got != tt.want {
+			t.Errorf("%v: %d modified to %d; want %d", tt.status, tt.v, got, tt.want)
+		}
+		log := strings.Join(g.Log, "\n")
+		if tt.log == "" && strings.Contains(log, "modifies") || !strings.Contains(log, tt.log) {
+			t.Errorf("%v: modifying %d logged %q; want %q", tt.status, tt.v, log, tt.log)
+		}
+	}
+}
+
+func TestArmyOccupiesChiefdom(t *testing.T) {
+	g := startedTestGame(t)
+	first := g.Board.Lands[toLandIndex(Natchez, 1)]
+	c := g.Board.Chiefdoms[first.Index]
+	c.IsControlled = true
+	g.Board.PeacePipes[first.Index] = true
+	h := g.Board.findHostile(Natchez)
+	h.LandIndex = toLandIndex(Natchez, 2)
+	score, cup := g.Score(), len(g.Cup)
+
+	g.AdvancingArmies = []Tribe{Natchez, Natchez}
+	stateAdvanceHostile{}.handle(g)
+	if h.LandIndex != first.Index {
+		t.Fatalf("the army is in %s; want %s", g.Board.Lands[h.LandIndex].Name, first.Name)
+	}
+	if g.Board.controls(c) || g.Score() != score-c.getValue() {
+		t.Error("the occupied chiefdom still counts as controlled")
+	}
+	stateAdvanceHostile{}.handle(g)
+	if h.LandIndex != first.Index {
+		t.Error("the army advanced past space 1")
+	}
+
+	stateEndOfTurnPhase{}.handle(g)
+	if g.Board.Chiefdoms[first.Index] != nil || len(g.Cup) != cup+1 {
+		t.Error("the occupied chiefdom wasn't lost, with its counter going back in the cup")
+	}
+	if g.Board.PeacePipes[first.Index] {
+		t.Error("the peace pipe is still on the lost chiefdom")
+	}
+}
+
+func TestCaddoOrShawneeAdvance(t *testing.T) {
+	tests := []struct {
+		caddo, shawnee int
+		want           Tribe
+	}{
+		{6, 6, Caddo},
+		{6, 3, Shawnee},
+		{2, 3, Caddo},
+	}
+	for _, tt := range tests {
+		g := startedTestGame(t)
+		caddo, shawnee := g.Board.findHostile(Caddo), g.Board.findHostile(Shawnee)
+		caddo.LandIndex = toLandIndex(Caddo, tt.caddo)
+		shawnee.LandIndex = toLandIndex(Shawnee, tt.shawnee)
+		g.AdvancingArmies = []Tribe{CaddoOrShawnee}
+		stateAdvanceHostile{}.handle(g)
+		want := map[Tribe]int{Caddo: tt.caddo, Shawnee: tt.shawnee}
+		want[tt.want]--
+		if got := (map[Tribe]int{Caddo: g.Board.hostileSpace(Caddo), Shawnee: g.Board.hostileSpace(Shawnee)}); !reflect.DeepEqual(got, want) {
+			t.Errorf("Caddo on %d, Shawnee on %d: the armies moved to %v; want %v", tt.caddo, tt.shawnee, got, want)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package mb
+
+import (
+	"fmt"
+	"strings"
+)
+
+// invariantLogLines is how much of the log an InvariantError includes.
+const invariantLogLines = 10
+
+// InvariantError reports a board that breaks the rules of the game, found
+// after a state was handled in debug mode.
+type InvariantError struct {
+	State   string   // the state that was handled
+	Err     error    // what's wrong with the board
+	LogTail []string // the end of the game log
+}
+
+func (e *InvariantError) Error() string {
+	return fmt.Sprintf("Invariant broken after %s: %s\nLog:\n  %s",
+		e.State, e.Err, strings.Join(e.LogTail, "\n  "))
+}
+
+// checkInvariants panics with an InvariantError if the board breaks the
+// rules after handling the given state.
+func (g *Game) checkInvariants(s state) {
+	err := g.Board.checkInvariants(len(g.Cup))
+	if err == nil {
+		return
+	}
+	var tail []string
+	for i := len(g.Log) - 1; i >= 0 && len(tail) < invariantLogLines; i-- {
+		if strings.TrimSpace(g.Log[i]) != "" {
+			tail = append([]string{strings.TrimSpace(g.Log[i])}, tail...)
+		}
+	}
+	panic(&InvariantError{State: fmt.Sprintf("%T", s), Err: err, LogTail: tail})
+}
+
+// checkInvariants checks the things that must always be true of the board,
+// given the number of counters left in the cup:
+//
+//   - each warpath has at most one peace pipe, no further out than space 5;
+//   - there are no chiefdoms in the wilderness;
+//   - the counters in the cup and on the board add up to 25;
+//   - the action points aren't negative;
+//   - the palisade marker is on the palisade track;
+//   - the hostile markers are where they can be (see checkHostiles).
+func (b Board) checkInvariants(inCup int) error {
+	for _, t := range tribes {
+		pipes := 0
+		for n := 1; n <= 6; n++ {
+			if !b.PeacePipes[toLandIndex(t, n)] {
+				continue
+			}
+			pipes++
+			if n > 5 {
+				return fmt.Errorf("There is a peace pipe in the %s homeland.", t)
+			}
+		}
+		if pipes > 1 {
+			return fmt.Errorf("The %s warpath has %d peace pipes.", t, pipes)
+		}
+	}
+
+	onBoard := 0
+	for i, c := range b.Chiefdoms {
+		if c == nil {
+			continue
+		}
+		if b.Lands[i].IsWilderness {
+			return fmt.Errorf("There is a chiefdom in the wilderness at %s.", b.Lands[i].Name)
+		}
+		if c.Counter != nil {
+			onBoard++
+		}
+	}
+	if onBoard+inCup != counterCount {
+		return fmt.Errorf("There are %d counters on the board and %d in the cup; there must be %d in all.",
+			onBoard, inCup, counterCount)
+	}
+
+	if b.ActionPoints < 0 {
+		return fmt.Errorf("There are %d action points.", b.ActionPoints)
+	}
+	if b.PalisadeIndex < 0 || b.PalisadeIndex >= len(b.Palisades) {
+		return fmt.Errorf("The palisade marker is at %d, off the track of %d.", b.PalisadeIndex, len(b.Palisades))
+	}
+	return b.checkHostiles()
+}
@@ -0,0 +1,188 @@
+package mb
+
+import "sort"
+
+// Odds gives the chances of what the next chiefdom counter drawn from the
+// cup and the next History Card will be.
+type Odds struct {
+	Cup  CupOdds
+	Card CardOdds
+}
+
+// CupOdds gives the chances for the next counter drawn from the cup.  A new
+// chiefdom shows its plain face, so Values and GreenBirdman are for that
+// face.
+type CupOdds struct {
+	Counters     int // how many counters are left in the cup
+	Goods        []GoodOdds
+	Values       []ValueOdds
+	GreenBirdman float64
+}
+
+// GoodOdds is the chance of drawing a counter with the given trade good.
+type GoodOdds struct {
+	Good   TradeGood
+	Chance float64
+}
+
+// ValueOdds is the chance of drawing a counter with the given value.
+type ValueOdds struct {
+	Value  int
+	Chance float64
+}
+
+// CardOdds gives the chances for the next History Card.  Cards is how many
+// different cards it might be.
+type CardOdds struct {
+	Cards  int
+	Tribes []TribeOdds // the five tribes, then the Spanish
+}
+
+// TribeOdds gives the chances of what the next History Card does to a
+// tribe.  Ascendant and Declining include cards that modify All.
+type TribeOdds struct {
+	Tribe     Tribe
+	Revolt    float64
+	Advance   float64 // the tribe's army advances at least once
+	Ascendant float64
+	Declining float64
+}
+
+// Odds works out the chances of what the next counter and card will be.  It
+// uses only what the player could know: the counters that aren't on the
+// board, the cards not yet drawn and how many cards of each era are left in
+// the section of the deck being drawn from.  With cheat set it looks at
+// the counter and card that will actually be drawn instead, which is meant
+// for testing.
+func (g *Game) Odds(cheat bool) Odds {
+	return Odds{Cup: g.cupOdds(cheat), Card: g.cardOdds(cheat)}
+}
+
+func (g *Game) cupOdds(cheat bool) CupOdds {
+	o := CupOdds{Counters: len(g.Cup)}
+	counters := g.Cup
+	if cheat && len(counters) > 0 {
+		counters = counters[:1]
+	}
+	if len(counters) == 0 {
+		return o
+	}
+	w := 1 / float64(len(counters))
+	goods := make(map[TradeGood]float64)
+	values := make(map[int]float64)
+	for _, c := range counters {
+		goods[c.Good] += w
+		values[c.Plain.Value] += w
+		if c.Plain.IsGreenBird {
+			o.GreenBirdman += w
+		}
+	}
+	for t, p := range goods {
+		o.Goods = append(o.Goods, GoodOdds{Good: t, Chance: p})
+	}
+	sort.Slice(o.Goods, func(i, j int) bool { return o.Goods[i].Good < o.Goods[j].Good })
+	for v, p := range values {
+		o.Values = append(o.Values, ValueOdds{Value: v, Chance: p})
+	}
+	sort.Slice(o.Values, func(i, j int) bool { return o.Values[i].Value < o.Values[j].Value })
+	return o
+}
+
+// nextCards returns the cards the next History Card might be, with the
+// chance of each.  The next card comes from the first section of the deck
+// with cards left, and the era counts of that section are known.  Any
+// unseen card of an era is as likely as any other to be the one drawn,
+// except for the Spanish cards, each of which is known to be in its own
+// final stack.
+func (g *Game) nextCards(cheat bool) map[*HistoryCard]float64 {
+	cards := make(map[*HistoryCard]float64)
+	if len(g.HistoryDeck) == 0 {
+		return cards
+	}
+	if cheat {
+		cards[g.HistoryDeck[0]] = 1
+		return cards
+	}
+	var section Pile
+	for _, r := range g.sectionRanges() {
+		if r[1] > r[0] {
+			section = g.HistoryDeck[r[0]:r[1]]
+			break
+		}
+	}
+	eras := make(map[Era]int)
+	for _, c := range section {
+		eras[c.Era]++
+	}
+	unseen := splitByEra(g.HistoryDeck)
+	for _, c := range section {
+		if c.Era == Spanish {
+			cards[c] = 1 / float64(len(section))
+		}
+	}
+	for e, n := range eras {
+		if e == Spanish {
+			continue
+		}
+		w := float64(n) / float64(len(section)) / float64(len(unseen[e]))
+		for _, c := range unseen[e] {
+			cards[c] += w
+		}
+	}
+	return cards
+}
+
+func (g *Game) cardOdds(cheat bool) CardOdds {
+	cards := g.nextCards(cheat)
+	odds := make(map[Tribe]*TribeOdds)
+	o := CardOdds{Cards: len(cards)}
+	for _, t := range append(append([]Tribe(nil), tribes...), SpanishTribe) {
+		o.Tribes = append(o.Tribes, TribeOdds{Tribe: t})
+	}
+	for i := range o.Tribes {
+		odds[o.Tribes[i].Tribe] = &o.Tribes[i]
+	}
+	for c, w := range cards {
+		for t := range g.Board.advancedBy(c) {
+			odds[t].Advance += w
+		}
+		for _, e := range c.Effects {
+			switch e.Kind {
+			case RevoltEffect:
+				odds[e.Tribe].Revolt += w
+			case SetModifierEffect:
+				for _, t := range tribes {
+					if e.Tribe != All && e.Tribe != t {
+						continue
+					}
+					if e.IsAscendant {
+						odds[t].Ascendant += w
+					} else {
+						odds[t].Declining += w
+					}
+				}
+			}
+		}
+	}
+	return o
+}
+
+// advancedBy returns the armies a card advances at least once.  The army a
+// card advancing Caddo or Shawnee moves is the one nearer Cahokia when its
+// turn comes, as in the game.
+func (b Board) advancedBy(c *HistoryCard) map[Tribe]bool {
+	advanced := make(map[Tribe]bool)
+	spaces := map[Tribe]int{Caddo: b.hostileSpace(Caddo), Shawnee: b.hostileSpace(Shawnee)}
+	for _, e := range c.effects(AdvanceArmiesEffect) {
+		for _, t := range e.Tribes {
+			if t == CaddoOrShawnee {
+				t = caddoOrShawnee(spaces[Caddo], spaces[Shawnee])
+			}
+			advanced[t] = true
+			if spaces[t] > 1 {
+				spaces[t]--
+			}
+		}
+	}
+	return advanced
+}
@@ -0,0 +1,166 @@
+package mb
+
+import (
+	"math"
+	"testing"
+)
+
+// near reports whether two chances are equal but for rounding.
+func near(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+// tribeOdds returns the odds for a tribe.
+func tribeOdds(t *testing.T, o CardOdds, tribe Tribe) TribeOdds {
+	t.Helper()
+	for _, to := range o.Tribes {
+		if to.Tribe == tribe {
+			return to
+		}
+	}
+	t.Fatalf("there are no odds for %s", tribe)
+	return TribeOdds{}
+}
+
+func TestCardChancesSumToOne(t *testing.T) {
+	g := newTestGame(t, GameOptions{EarlyHopewellCards: Int(10)})
+	for len(g.HistoryDeck) > 0 {
+		total := 0.0
+		for _, p := range g.nextCards(false) {
+			total += p
+		}
+		if !near(total, 1) {
+			t.Fatalf("with %d cards left, the chances add up to %v", len(g.HistoryDeck), total)
+		}
+		g.drawHistoryCard()
+	}
+	if n := len(g.nextCards(false)); n != 0 {
+		t.Errorf("with no cards left, %d cards might be drawn", n)
+	}
+}
+
+func TestCupChancesSumToOne(t *testing.T) {
+	g := newTestGame(t, GameOptions{})
+	o := g.Odds(false).Cup
+	goods, values := 0.0, 0.0
+	for _, gc := range o.Goods {
+		goods += gc.Chance
+	}
+	for _, vc := range o.Values {
+		values += vc.Chance
+	}
+	if !near(goods, 1) || !near(values, 1) || o.Counters != len(g.Cup) {
+		t.Errorf("for %d counters, the trade good chances add up to %v and the value chances to %v", o.Counters, goods, values)
+	}
+}
+
+func TestCheatOdds(t *testing.T) {
+	g := newTestGame(t, GameOptions{})
+	g.HistoryDeck[0] = testCard(t, g, 19) // Moundville
+	o := g.Odds(true)
+
+	c := g.Cup[0]
+	if len(o.Cup.Goods) != 1 || o.Cup.Goods[0] != (GoodOdds{c.Good, 1}) ||
+		len(o.Cup.Values) != 1 || o.Cup.Values[0] != (ValueOdds{c.Plain.Value, 1}) {
+		t.Errorf("cheat cup odds are %+v; want the first counter, %s", o.Cup, c)
+	}
+
+	if o.Card.Cards != 1 {
+		t.Errorf("cheat card odds cover %d cards; want 1", o.Card.Cards)
+	}
+	want := map[Tribe]TribeOdds{
+		HoChunk:  {Tribe: HoChunk, Advance: 1},
+		Natchez:  {Tribe: Natchez, Advance: 1, Ascendant: 1},
+		Cherokee: {Tribe: Cherokee, Revolt: 1},
+	}
+	for _, to := range o.Card.Tribes {
+		w, ok := want[to.Tribe]
+		if !ok {
+			w = TribeOdds{Tribe: to.Tribe}
+		}
+		if to != w {
+			t.Errorf("cheat odds for %s are %+v; want %+v", to.Tribe, to, w)
+		}
+	}
+}
+
+func TestSpanishCardOnlyInItsSection(t *testing.T) {
+	g := newTestGame(t, GameOptions{EarlyHopewellCards: Int(10)})
+	for len(g.HistoryDeck) > 0 {
+		var section Pile
+		for _, r := range g.sectionRanges() {
+			if r[1] > r[0] {
+				section = g.HistoryDeck[r[0]:r[1]]
+				break
+			}
+		}
+		inSection := make(map[*HistoryCard]bool)
+		for _, c := range section {
+			inSection[c] = true
+		}
+		for c, p := range g.nextCards(false) {
+			if c.Era != Spanish {
+				continue
+			}
+			if !inSection[c] {
+				t.Fatalf("with %d cards left, Spanish card %s is counted outside its section", len(g.HistoryDeck), c)
+			}
+			if !near(p, 1/float64(len(section))) {
+				t.Errorf("with %d cards left, Spanish card %s has a chance of %v; want 1 in %d", len(g.HistoryDeck), c, p, len(section))
+			}
+		}
+		g.drawHistoryCard()
+	}
+}
+
+func TestCaddoOrShawneeOdds(t *testing.T) {
+	tests := []struct {
+		caddo, shawnee int
+		want           Tribe
+	}{
+		{6, 6, Caddo},
+		{6, 4, Shawnee},
+		{3, 4, Caddo},
+	}
+	for _, tt := range tests {
+		g := newTestGame(t, GameOptions{})
+		g.HistoryDeck[0] = testCard(t, g, 37) // The Chunkey Game
+		g.Board.findHostile(Caddo).LandIndex = toLandIndex(Caddo, tt.caddo)
+		g.Board.findHostile(Shawnee).LandIndex = toLandIndex(Shawnee, tt.shawnee)
+		o := g.Odds(true).Card
+		for _, tribe := range []Tribe{Caddo, Shawnee} {
+			want := 0.0
+			if tribe == tt.want {
+				want = 1
+			}
+			if got := tribeOdds(t, o, tribe).Advance; got != want {
+				t.Errorf("Caddo on %d, Shawnee on %d: %s advances with a chance of %v; want %v",
+					tt.caddo, tt.shawnee, tribe, got, want)
+			}
+		}
+	}
+}
+
+func TestAllModifierOdds(t *testing.T) {
+	tests := []struct {
+		number               int
+		ascendant, declining float64
+	}{
+		{44, 1, 0}, // Pottery
+		{49, 0, 1}, // Black Drink
+	}
+	for _, tt := range tests {
+		g := newTestGame(t, GameOptions{})
+		g.HistoryDeck[0] = testCard(t, g, tt.number)
+		o := g.Odds(true).Card
+		for _, tribe := range tribes {
+			if to := tribeOdds(t, o, tribe); to.Ascendant != tt.ascendant || to.Declining != tt.declining {
+				t.Errorf("card %d: %s is ascendant %v and declining %v; want %v and %v",
+					tt.number, tribe, to.Ascendant, to.Declining, tt.ascendant, tt.declining)
+			}
+		}
+		if to := tribeOdds(t, o, SpanishTribe); to.Ascendant != 0 || to.Declining != 0 {
+			t.Errorf("card %d modifies the Spanish: %+v", tt.number, to)
+		}
+	}
+}
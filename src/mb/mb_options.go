@@ -0,0 +1,142 @@
+package mb
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Difficulty names one of the preset rule variants.
+type Difficulty string
+
+const (
+	Easy   Difficulty = "easy"
+	Normal Difficulty = "normal"
+	Hard   Difficulty = "hard"
+)
+
+// GameOptions holds the rule variants a game is played with.  Zero-valued
+// fields are filled in from the preset named by Difficulty, so a caller can
+// pick a preset and override only the values it cares about.  Counts that
+// can meaningfully be 0 are pointers, so that nil means unset.
+type GameOptions struct {
+	Difficulty          Difficulty
+	HostileBattleValues []int      // starting battle values, indexed by Tribe
+	Palisades           []Palisade // the palisade track, strongest first
+	EarlyHopewellCards  *int       // Hopewell cards on top of the History Deck, 0-12
+	Seed                int64      // random number seed; 0 picks one from the clock
+
+	// Data files to use instead of the embedded data; see LoadGameData.
+	CardsFile    string
+	CountersFile string
+	BoardFile    string
+}
+
+var presets = map[Difficulty]GameOptions{
+	Easy: {
+		Difficulty:          Easy,
+		HostileBattleValues: []int{3, 2, 1, 1, 2},
+		Palisades: []Palisade{
+			{"5G", 5},
+			{"4F", 4},
+			{"4E", 4},
+			{"4D", 4},
+			{"3C", 3},
+			{"3B", 3},
+			{"2A", 2},
+		},
+		EarlyHopewellCards: Int(12),
+	},
+	Normal: {
+		Difficulty:          Normal,
+		HostileBattleValues: []int{4, 3, 2, 2, 3},
+		Palisades: []Palisade{
+			{"4F", 4},
+			{"4E", 4},
+			{"4D", 4},
+			{"3C", 3},
+			{"3B", 3},
+			{"2A", 2},
+		},
+		EarlyHopewellCards: Int(10),
+	},
+	Hard: {
+		Difficulty:          Hard,
+		HostileBattleValues: []int{5, 4, 3, 3, 4},
+		Palisades: []Palisade{
+			{"4D", 4},
+			{"3C", 3},
+			{"3B", 3},
+			{"2A", 2},
+		},
+		EarlyHopewellCards: Int(8),
+	},
+}
+
+// Int returns a pointer to n, for setting options such as
+// EarlyHopewellCards.
+func Int(n int) *int {
+	return &n
+}
+
+// NewGameOptions returns the preset options for the named difficulty.
+func NewGameOptions(d Difficulty) (GameOptions, error) {
+	p, ok := presets[Difficulty(strings.ToLower(string(d)))]
+	if !ok {
+		return GameOptions{}, fmt.Errorf("Unknown difficulty %q; must be easy, normal or hard.", d)
+	}
+	return p.copy(), nil
+}
+
+// copy returns a copy of the options that shares no slices with o.
+func (o GameOptions) copy() GameOptions {
+	c := o
+	c.HostileBattleValues = append([]int(nil), o.HostileBattleValues...)
+	c.Palisades = append([]Palisade(nil), o.Palisades...)
+	if o.EarlyHopewellCards != nil {
+		c.EarlyHopewellCards = Int(*o.EarlyHopewellCards)
+	}
+	return c
+}
+
+// resolve fills in any unset values from the options' preset and checks
+// that the result is playable.
+func (o GameOptions) resolve() (GameOptions, error) {
+	if o.Difficulty == "" {
+		o.Difficulty = Normal
+	}
+	p, err := NewGameOptions(o.Difficulty)
+	if err != nil {
+		return o, err
+	}
+	o.Difficulty = p.Difficulty
+	if o.HostileBattleValues == nil {
+		o.HostileBattleValues = p.HostileBattleValues
+	}
+	if o.Palisades == nil {
+		o.Palisades = p.Palisades
+	}
+	if o.EarlyHopewellCards == nil {
+		o.EarlyHopewellCards = p.EarlyHopewellCards
+	}
+
+	if n := len(o.HostileBattleValues); n != len(tribes) {
+		return o, fmt.Errorf("%d hostile battle values given; need one for each of the %d tribes.", n, len(tribes))
+	}
+	for i, v := range o.HostileBattleValues {
+		if v < 1 || v > 6 {
+			return o, fmt.Errorf("Battle value %d for %s must be between 1 and 6.", v, Tribe(i))
+		}
+	}
+	if len(o.Palisades) == 0 {
+		return o, fmt.Errorf("The palisade track needs at least one space.")
+	}
+	for _, p := range o.Palisades {
+		if p.Value < 1 {
+			return o, fmt.Errorf("Palisade %s has value %d; must be at least 1.", p.Label, p.Value)
+		}
+	}
+	if n := *o.EarlyHopewellCards; n < 0 || n > 12 {
+		return o, fmt.Errorf("%d early Hopewell cards requested; must be between 0 and 12.", n)
+	}
+	return o.copy(), nil
+}
@@ -0,0 +1,221 @@
+package mb
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// actionAliases are other words a player can use for an action, besides its
+// code and its name.
+var actionAliases = map[string]string{
+	"pipe":    "ppa",
+	"advance": "ppa",
+	"mound":   "mnd",
+	"fort":    "frt",
+	"end":     "pas",
+	"done":    "pas",
+	"exit":    "qui",
+}
+
+// tokenize splits a command into words.  Words are separated by any amount
+// of white space, and a name containing spaces can be given in single or
+// double quotes.
+func tokenize(s string) ([]string, error) {
+	var tokens []string
+	var word []rune
+	inWord := false
+	var quote rune
+	for _, r := range s {
+		switch {
+		case quote != 0 && r == quote:
+			quote = 0
+		case quote != 0:
+			word = append(word, r)
+		case r == '"' || r == '\'':
+			quote, inWord = r, true
+		case unicode.IsSpace(r):
+			if inWord {
+				tokens = append(tokens, string(word))
+				word, inWord = nil, false
+			}
+		default:
+			word, inWord = append(word, r), true
+		}
+	}
+	if quote != 0 {
+		return nil, errSyntax(s, "There is no closing %c.", quote)
+	}
+	if inWord {
+		tokens = append(tokens, string(word))
+	}
+	return tokens, nil
+}
+
+// findActionSpec finds the action named at the start of the tokens, by its
+// code, its name or an alias, ignoring case.  Names can be more than one
+// word, typed as separate words or in quotes, so it also returns how many
+// tokens the action took up.
+func findActionSpec(tokens []string) (ActionSpec, int, error) {
+	if len(tokens) == 0 {
+		return ActionSpec{}, 0, errUnknownAction("", nil)
+	}
+	var found ActionSpec
+	n := 0
+	for _, as := range actions {
+		words := strings.Fields(as.Abbr)
+		if len(words) > n && len(words) <= len(tokens) && equalWords(words, tokens) {
+			found, n = as, len(words)
+		}
+		if n == 0 && strings.EqualFold(as.Abbr, tokens[0]) {
+			found, n = as, 1
+		}
+	}
+	if n > 0 {
+		return found, n, nil
+	}
+	name := strings.ToLower(tokens[0])
+	if code, ok := actionAliases[name]; ok {
+		name = code
+	}
+	for _, as := range actions {
+		if as.Name == name {
+			return as, 1, nil
+		}
+	}
+	return ActionSpec{}, 0, errUnknownAction(tokens[0], suggest(tokens[0], actionWords()))
+}
+
+// equalWords reports whether tokens begins with words, ignoring case.
+func equalWords(words, tokens []string) bool {
+	for i, w := range words {
+		if !strings.EqualFold(w, tokens[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// actionWords lists the words that name actions.
+func actionWords() []string {
+	var words []string
+	for _, as := range actions {
+		words = append(words, as.Name, as.Abbr)
+	}
+	for alias := range actionAliases {
+		words = append(words, alias)
+	}
+	return words
+}
+
+// Ways a name can match what the player typed, best last.
+const (
+	noMatch = iota
+	wordsMatch
+	prefixMatch
+	exactMatch
+)
+
+// matchName reports how well name matches what the player typed, ignoring
+// case.  Besides the whole name or its beginning, the player can give the
+// beginning of each word, so "bot cr" matches "Bottle Creek".
+func matchName(name, typed string) int {
+	n, t := strings.ToLower(name), strings.ToLower(strings.Join(strings.Fields(typed), " "))
+	switch {
+	case n == t:
+		return exactMatch
+	case strings.HasPrefix(n, t):
+		return prefixMatch
+	}
+	nw, tw := strings.Fields(n), strings.Fields(t)
+	if len(tw) == 0 || len(tw) > len(nw) {
+		return noMatch
+	}
+	for i, w := range tw {
+		if !strings.HasPrefix(nw[i], w) {
+			return noMatch
+		}
+	}
+	return wordsMatch
+}
+
+// bestMatches returns the indexes of the names that best match what the
+// player typed.  An exact match beats a match of the beginning of the name.
+func bestMatches(names []string, typed string) []int {
+	best := noMatch
+	var found []int
+	for i, name := range names {
+		m := matchName(name, typed)
+		switch {
+		case m == noMatch || m < best:
+		case m > best:
+			best, found = m, []int{i}
+		default:
+			found = append(found, i)
+		}
+	}
+	return found
+}
+
+// maxSuggestionDistance is how many typing mistakes a suggestion can fix.
+const maxSuggestionDistance = 2
+
+// suggest returns the candidates closest to what the player typed, if any
+// are within a couple of typing mistakes of it or of its beginning.
+func suggest(typed string, candidates []string) []string {
+	t := strings.ToLower(strings.TrimSpace(typed))
+	if t == "" {
+		return nil
+	}
+	best := maxSuggestionDistance + 1
+	var found []string
+	seen := make(map[string]bool)
+	for _, c := range candidates {
+		lc := strings.ToLower(c)
+		d := editDistance(t, lc)
+		if len(lc) > len(t) {
+			if dp := editDistance(t, lc[:len(t)]); dp < d {
+				d = dp
+			}
+		}
+		if d >= len(t) || d > best || seen[lc] {
+			continue
+		}
+		if d < best {
+			best, found, seen = d, nil, make(map[string]bool)
+		}
+		found = append(found, c)
+		seen[lc] = true
+	}
+	sort.Strings(found)
+	return found
+}
+
+// editDistance is the number of characters that must be inserted, deleted
+// or changed to turn a into b.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = prev[j-1] + cost
+			if prev[j]+1 < cur[j] {
+				cur[j] = prev[j] + 1
+			}
+			if cur[j-1]+1 < cur[j] {
+				cur[j] = cur[j-1] + 1
+			}
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
@@ -0,0 +1,135 @@
+package mb
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"", nil},
+		{"  inc   Caddo ", []string{"inc", "Caddo"}},
+		{"mnd Bottle Creek", []string{"mnd", "Bottle", "Creek"}},
+		{`mnd "Bottle Creek"`, []string{"mnd", "Bottle Creek"}},
+		{"build 'Bottle  Creek'", []string{"build", "Bottle  Creek"}},
+		{`mnd 'Bottle "Creek"'`, []string{"mnd", `Bottle "Creek"`}},
+		{`pow Ho"Chunk"`, []string{"pow", "HoChunk"}},
+		{`pas ""`, []string{"pas", ""}},
+	}
+	for _, tt := range tests {
+		got, err := tokenize(tt.in)
+		if err != nil {
+			t.Errorf("tokenize(%q): %v", tt.in, err)
+		} else if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("tokenize(%q) = %q; want %q", tt.in, got, tt.want)
+		}
+	}
+
+	for _, in := range []string{`mnd "Bottle Creek`, "mnd 'Bottle"} {
+		if _, err := tokenize(in); err == nil || err.(*RuleError).Code != ErrSyntax {
+			t.Errorf("tokenize(%q) returned error %v; want a %s error", in, err, ErrSyntax)
+		}
+	}
+}
+
+func TestFindActionSpec(t *testing.T) {
+	tests := []struct {
+		tokens []string
+		want   string
+		n      int
+	}{
+		{[]string{"inc", "Caddo"}, "inc", 1},
+		{[]string{"INC"}, "inc", 1},
+		{[]string{"Incorporate", "Caddo"}, "inc", 1},
+		{[]string{"peace", "pipe", "Caddo"}, "ppa", 2},
+		{[]string{"Peace Pipe", "Caddo"}, "ppa", 1},
+		{[]string{"pipe", "Caddo"}, "ppa", 1},
+		{[]string{"Mound", "Kincaid"}, "mnd", 1},
+		{[]string{"build", "Kincaid"}, "mnd", 1},
+		{[]string{"done"}, "pas", 1},
+		{[]string{"exit"}, "qui", 1},
+	}
+	for _, tt := range tests {
+		as, n, err := findActionSpec(tt.tokens)
+		if err != nil {
+			t.Errorf("findActionSpec(%q): %v", tt.tokens, err)
+		} else if as.Name != tt.want || n != tt.n {
+			t.Errorf("findActionSpec(%q) = %s taking %d words; want %s taking %d", tt.tokens, as.Name, n, tt.want, tt.n)
+		}
+	}
+
+	errTests := []struct {
+		tokens      []string
+		suggestions []string
+	}{
+		{nil, nil},
+		{[]string{"peace"}, []string{"Peace Pipe"}},
+		{[]string{"incorprate", "Caddo"}, []string{"Incorporate"}},
+		{[]string{"mund"}, []string{"mnd", "mound"}},
+		{[]string{"xyzzy"}, nil},
+	}
+	for _, tt := range errTests {
+		_, _, err := findActionSpec(tt.tokens)
+		e, ok := err.(*RuleError)
+		if !ok || e.Code != ErrUnknownAction {
+			t.Errorf("findActionSpec(%q) returned error %v; want a %s error", tt.tokens, err, ErrUnknownAction)
+		} else if !reflect.DeepEqual(e.Suggestions, tt.suggestions) {
+			t.Errorf("findActionSpec(%q) suggests %q; want %q", tt.tokens, e.Suggestions, tt.suggestions)
+		}
+	}
+}
+
+func TestBestMatches(t *testing.T) {
+	names := []string{"Bottle Creek", "Bynum", "Moundville", "Mound City", "Mound"}
+	tests := []struct {
+		typed string
+		want  []int
+	}{
+		{"bottle creek", []int{0}},
+		{"BOT", []int{0}},
+		{"bot cr", []int{0}},
+		{"b", []int{0, 1}},
+		{"mound", []int{4}},
+		{"moun", []int{2, 3, 4}},
+		{"mo c", []int{3}},
+		{"creek", nil},
+		{"bottle creek mound", nil},
+		{"", []int{0, 1, 2, 3, 4}},
+	}
+	for _, tt := range tests {
+		if got := bestMatches(names, tt.typed); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("bestMatches(%q) = %v; want %v", tt.typed, got, tt.want)
+		}
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	tests := []struct {
+		typed      string
+		candidates []string
+		want       []string
+	}{
+		{"incorprate", actionWords(), []string{"Incorporate"}},
+		{"pase", actionWords(), []string{"Pass", "pas"}},
+		{"qiut", actionWords(), []string{"Quit", "qui"}},
+		{"fortfy", actionWords(), []string{"Fortify"}},
+		{"bottel creek", []string{"Bottle Creek", "Moundville"}, []string{"Bottle Creek"}},
+		{"cado", []string{"Natchez", "Cahokia", "Caddo"}, []string{"Caddo", "Cahokia"}},
+		{"xyzzy", actionWords(), nil},
+		{"  ", actionWords(), nil},
+		{"p", []string{"x"}, nil},
+
+		// a closer match replaces the ones found so far, and a name is
+		// suggested only once, whatever its case
+		{"caddi", []string{"Cahokia", "Caddy", "Caddo", "CADDO", "Caddy"}, []string{"Caddo", "Caddy"}},
+		{"cadd", []string{"Cahokia", "Cahokia", "Caddo"}, []string{"Caddo"}},
+	}
+	for _, tt := range tests {
+		if got := suggest(tt.typed, tt.candidates); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("suggest(%q, %q) = %q; want %q", tt.typed, tt.candidates, got, tt.want)
+		}
+	}
+}
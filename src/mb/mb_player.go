@@ -0,0 +1,129 @@
+package mb
+
+import (
+	"math/rand"
+	"strings"
+)
+
+// Player chooses moves.  It's given the board, the prompt being answered and
+// the inputs the game would accept, and returns the request to make.
+type Player interface {
+	Play(b *Board, p Prompt, legal []Input) Request
+}
+
+// NextRequest asks the player for the game's next request.  It returns false
+// if the game is over or there's nothing the player can do.
+func (g *Game) NextRequest(p Player) (Request, bool) {
+	if g.Response == nil || g.IsOver() {
+		return Request{}, false
+	}
+	legal := g.LegalInputs()
+	if len(legal) == 0 {
+		return Request{}, false
+	}
+	return p.Play(&g.Board, g.Response.Prompt, legal), true
+}
+
+// RandomPlayer picks any legal input.  It's the baseline other players can
+// be measured against.
+type RandomPlayer struct {
+	rng *rand.Rand
+}
+
+// NewRandomPlayer makes a RandomPlayer with its own random number generator.
+func NewRandomPlayer(seed int64) *RandomPlayer {
+	return &RandomPlayer{rng: rand.New(rand.NewSource(seed))}
+}
+
+func (p *RandomPlayer) Play(_ *Board, _ Prompt, legal []Input) Request {
+	return Request{Input: legal[p.rng.Intn(len(legal))]}
+}
+
+// HeuristicPlayer plays by rules of thumb: repair a breach first, then mound
+// every chiefdom it controls, since a mound saves a chiefdom from being lost
+// to an occupying army.  While a chiefdom waits for its mound it passes,
+// keeping its APs for the mound, rather than incorporating or exploring.
+// Otherwise it incorporates the chiefdoms it has the best odds of winning
+// and explores with free peace pipe advances.  It never quits.
+type HeuristicPlayer struct{}
+
+func (HeuristicPlayer) Play(b *Board, _ Prompt, legal []Input) Request {
+	best, bestScore := legal[0], -1.0
+	for _, in := range legal {
+		if s := scoreInput(b, in); s > bestScore {
+			best, bestScore = in, s
+		}
+	}
+	return Request{Input: best}
+}
+
+// scoreInput rates an input for HeuristicPlayer; higher is better.
+func scoreInput(b *Board, in Input) float64 {
+	name, target := string(in), ""
+	if i := strings.Index(name, " "); i >= 0 {
+		name, target = name[:i], name[i+1:]
+	}
+	switch name {
+	case "n":
+		return 1
+	case "y", "qui":
+		return 0
+	case "pas":
+		return 0.5
+	case "rep":
+		return 100
+	case "mnd":
+		for _, l := range b.Lands {
+			if l.Name == target && b.Chiefdoms[l.Index] != nil {
+				c := b.Chiefdoms[l.Index]
+				return 50 + 5*float64(c.Counter.Mounded.Value)
+			}
+		}
+		return 1
+	case "inc":
+		if awaitingMound(b) {
+			return 0.25
+		}
+		// the odds of rolling higher than the chiefdom's value
+		t := tribeNameLookup[target]
+		old, land := b.findPeacePipeLands(t)
+		c := b.Chiefdoms[land.Index]
+		if c == nil {
+			return 1
+		}
+		v := normalizeDie(c.getValue() + b.WarpathStatus.modifierFor(t))
+		return 10 + 50*incorporationOdds(v, old != Land{})
+	case "ppa":
+		if awaitingMound(b) {
+			return 0.25
+		}
+	}
+	return 5
+}
+
+// awaitingMound reports whether the player controls a chiefdom that has no
+// mound and isn't occupied, so could be mounded.
+func awaitingMound(b *Board) bool {
+	for _, c := range b.Chiefdoms {
+		if b.controls(c) && !c.IsMounded {
+			return true
+		}
+	}
+	return false
+}
+
+// incorporationOdds is the chance of rolling more than v, on one die or on
+// the better of two dice for a busk.
+func incorporationOdds(v int, busk bool) float64 {
+	switch {
+	case v < 1:
+		return 1
+	case v >= 6:
+		return 0
+	}
+	fail := float64(v) / 6
+	if busk {
+		fail *= fail
+	}
+	return 1 - fail
+}
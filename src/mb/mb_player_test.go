@@ -0,0 +1,119 @@
+package mb
+
+import "testing"
+
+// playOut plays a game to the end with the given player and returns its
+// score.
+func playOut(t *testing.T, g *Game, p Player) int {
+	t.Helper()
+	for n := 0; n < maxRolloutRequests && !g.IsOver(); n++ {
+		q, ok := g.NextRequest(p)
+		if !ok {
+			t.Fatalf("no request at %q", g.Response.Prompt)
+		}
+		g.HandleRequest(q)
+		if e := g.Failed(); e != nil {
+			t.Fatalf("the game failed: %s", e)
+		}
+	}
+	if !g.IsOver() {
+		t.Fatalf("the game didn't end after %d requests", maxRolloutRequests)
+	}
+	return g.Result().Score
+}
+
+func TestNextRequest(t *testing.T) {
+	g := newTestGame(t, GameOptions{})
+	if _, ok := g.NextRequest(HeuristicPlayer{}); ok {
+		t.Error("there's a request before the game has started")
+	}
+
+	g = startedTestGame(t)
+	q, ok := g.NextRequest(HeuristicPlayer{})
+	if !ok {
+		t.Fatal("there's no request in the Action Phase")
+	}
+	legal := false
+	for _, in := range g.LegalInputs() {
+		legal = legal || in == q.Input
+	}
+	if !legal {
+		t.Errorf("the request %q isn't a legal input", q.Input)
+	}
+
+	g.HandleRequest(Request{Input: "qui"})
+	g.HandleRequest(Request{Input: "y"})
+	if _, ok := g.NextRequest(HeuristicPlayer{}); ok || !g.IsOver() {
+		t.Error("there's a request after the game has ended")
+	}
+}
+
+func TestRandomPlayer(t *testing.T) {
+	g := startedTestGame(t)
+	legal := g.LegalInputs()
+	a, b := NewRandomPlayer(1), NewRandomPlayer(1)
+	seen := make(map[Input]bool)
+	for i := 0; i < 50; i++ {
+		in := a.Play(&g.Board, g.Response.Prompt, legal).Input
+		if again := b.Play(&g.Board, g.Response.Prompt, legal).Input; again != in {
+			t.Fatalf("players with the same seed chose %q and %q", in, again)
+		}
+		seen[in] = true
+	}
+	for in := range seen {
+		found := false
+		for _, l := range legal {
+			found = found || l == in
+		}
+		if !found {
+			t.Errorf("chose %q, which isn't legal", in)
+		}
+	}
+	if len(seen) < 2 {
+		t.Errorf("chose only %v from %v", seen, legal)
+	}
+}
+
+func TestHeuristicPlayer(t *testing.T) {
+	g := startedTestGame(t)
+	kincaid := g.Board.Lands[toLandIndex(Natchez, 1)]
+	tests := []struct {
+		controlled bool
+		legal      []Input
+		want       Input
+	}{
+		{false, []Input{"pas", "ppa Natchez", "inc Caddo"}, "inc Caddo"},
+		{false, []Input{"pas", "ppa Natchez"}, "ppa Natchez"},
+		{true, []Input{"pas", "inc Caddo", "mnd Kincaid"}, "mnd Kincaid"},
+		{true, []Input{"inc Caddo", "ppa Natchez", "pas"}, "pas"},
+		{true, []Input{"mnd Kincaid", "rep"}, "rep"},
+		{false, []Input{"y", "n"}, "n"},
+	}
+	for _, tt := range tests {
+		g.Board.Chiefdoms[kincaid.Index].IsControlled = tt.controlled
+		if got := (HeuristicPlayer{}).Play(&g.Board, "", tt.legal).Input; got != tt.want {
+			t.Errorf("with Kincaid controlled %v, chose %q from %v; want %q", tt.controlled, got, tt.legal, tt.want)
+		}
+	}
+}
+
+func TestHeuristicBeatsRandom(t *testing.T) {
+	var heuristic, random int
+	for seed := int64(1); seed <= 40; seed++ {
+		for _, p := range []struct {
+			player Player
+			score  *int
+		}{
+			{HeuristicPlayer{}, &heuristic},
+			{NewRandomPlayer(seed), &random},
+		} {
+			g := newTestGame(t, GameOptions{Seed: seed})
+			g.StartGame()
+			withoutEvents(g)
+			*p.score += playOut(t, g, p.player)
+		}
+	}
+	if heuristic <= random {
+		t.Errorf("over 40 games the heuristic player scored %d and the random player %d", heuristic, random)
+	}
+}
@@ -0,0 +1,65 @@
+package mb
+
+import (
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"strings"
+)
+
+// InternalError reports a bug in the engine: handling a request panicked.
+// If the game's Rollback is set, it is rolled back to where it was before
+// the request, so the player can carry on with another move; otherwise it is
+// left where the panic stopped it.
+type InternalError struct {
+	Input   Input       // the request's input
+	State   string      // the state that was being handled
+	Message string      // what the panic said
+	Panic   interface{} `json:"-"`
+	Stack   string      `json:"-"`
+}
+
+func (e *InternalError) Error() string {
+	return fmt.Sprintf("Internal error in %s handling %q: %s", e.State, e.Input, e.Message)
+}
+
+// Unwrap returns the panic value if it was an error.
+func (e *InternalError) Unwrap() error {
+	err, _ := e.Panic.(error)
+	return err
+}
+
+// Failed returns the InternalError the last request ended with, or nil if
+// it was handled.
+func (g *Game) Failed() *InternalError {
+	var e *InternalError
+	if g.Response != nil && errors.As(g.Response.Error, &e) {
+		return e
+	}
+	return nil
+}
+
+// recoverFrom rolls the game back to the saved copy taken before handling
+// the request that panicked, if there is one, records the failure in the log and responds
+// with an InternalError.
+func (g *Game) recoverFrom(p interface{}, saved *Game, q Request) {
+	e := &InternalError{
+		Input:   q.Input,
+		State:   fmt.Sprintf("%T", g.State),
+		Message: fmt.Sprint(p),
+		Panic:   p,
+		Stack:   string(debug.Stack()),
+	}
+	if saved != nil {
+		*g = *saved
+	}
+	g.logEvent("%s", e)
+	for _, line := range strings.Split(strings.TrimSpace(e.Stack), "\n") {
+		g.logEvent("    %s", line)
+	}
+	prompt := Prompt("")
+	if g.Response != nil {
+		prompt = g.Response.Prompt
+	}
+	g.Response = &Response{Prompt: prompt, Error: e}
+}
@@ -0,0 +1,38 @@
+package mb
+
+import "testing"
+
+// statePanic panics when handled, like a state with a bug in it.
+type statePanic struct{}
+
+func (statePanic) handle(g *Game) state {
+	g.Board.ActionPoints += 10
+	panic("boom")
+}
+
+func TestRecoverRollsBack(t *testing.T) {
+	g := startedTestGame(t)
+	g.Rollback = true
+	g.State = statePanic{}
+	ap, requests := g.Board.ActionPoints, len(g.Requests)
+	g.HandleRequest(Request{Input: "pas"})
+	if g.Failed() == nil {
+		t.Fatal("a panic didn't end the request with an InternalError")
+	}
+	if g.Board.ActionPoints != ap || len(g.Requests) != requests {
+		t.Error("the game wasn't rolled back to before the request")
+	}
+}
+
+func TestRecoverWithoutRollback(t *testing.T) {
+	g := startedTestGame(t)
+	ap := g.Board.ActionPoints
+	g.State = statePanic{}
+	g.HandleRequest(Request{Input: "pas"})
+	if g.Failed() == nil {
+		t.Fatal("a panic didn't end the request with an InternalError")
+	}
+	if g.Board.ActionPoints != ap+10 {
+		t.Errorf("action points are %d; without Rollback they should be left at %d", g.Board.ActionPoints, ap+10)
+	}
+}
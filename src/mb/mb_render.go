@@ -0,0 +1,174 @@
+package mb
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// ANSI escape codes used when rendering in color.
+const (
+	ansiReset = "\x1b[0m"
+	ansiBold  = "\x1b[1m"
+	ansiDim   = "\x1b[2m"
+	ansiRed   = "\x1b[31m"
+	ansiGreen = "\x1b[32m"
+	ansiCyan  = "\x1b[36m"
+)
+
+const renderCellWidth = 15
+
+var tradeGoodAbbrs = map[TradeGood]string{
+	Hides:      "Hid",
+	Chert:      "Crt",
+	Feathers:   "Fea",
+	Copper:     "Cop",
+	Mica:       "Mic",
+	Chalcedony: "Chl",
+	Pipestone:  "Pip",
+	Obsidian:   "Obs",
+	Seashells:  "Sea",
+}
+
+// renderer accumulates board text, padding each cell to a fixed width
+// whether or not it contains escape codes.
+type renderer struct {
+	bytes.Buffer
+	color bool
+}
+
+// text writes s, in the given style if rendering in color.
+func (r *renderer) text(s, style string) {
+	if r.color && style != "" {
+		r.WriteString(style + s + ansiReset)
+	} else {
+		r.WriteString(s)
+	}
+}
+
+// cell writes the parts of a cell separated by spaces, padding it to the
+// cell width.
+func (r *renderer) cell(parts ...[2]string) {
+	n := 0
+	for i, p := range parts {
+		if i > 0 {
+			r.WriteString(" ")
+			n++
+		}
+		r.text(p[0], p[1])
+		n += len(p[0])
+	}
+	if n < renderCellWidth {
+		r.WriteString(strings.Repeat(" ", renderCellWidth-n))
+	}
+}
+
+func truncate(s string, n int) string {
+	if len(s) > n {
+		return s[:n]
+	}
+	return s
+}
+
+// Render draws the board as text: the five warpaths from Cahokia out to
+// the tribal homelands, with their lands, chiefdoms, peace pipes and hostile
+// armies, followed by the palisade track and the current turn's numbers.
+// If color is true the text includes ANSI color codes.
+//
+// In each land, a chiefdom is shown by its trade good and value, followed
+// by M if it's mounded, * if it has a green birdman, and C if it's
+// controlled, or O if it's controlled but occupied by a hostile army.  P is
+// the peace pipe, H is a hostile army with its battle value and S is the
+// Spanish.
+func (b *Board) Render(color bool) string {
+	r := &renderer{color: color}
+
+	r.cell([2]string{"Warpath", ansiBold})
+	for n := 1; n <= 6; n++ {
+		r.cell([2]string{fmt.Sprintf("%d", n), ansiBold})
+	}
+	r.WriteString("\n")
+
+	for _, t := range tribes {
+		r.cell([2]string{t.String(), ansiBold})
+		for n := 1; n <= 6; n++ {
+			l := b.Lands[toLandIndex(t, n)]
+			style := ""
+			if l.IsWilderness {
+				style = ansiDim
+			}
+			r.cell([2]string{truncate(l.Name, renderCellWidth-1), style})
+		}
+		r.WriteString("\n")
+
+		r.cell()
+		for n := 1; n <= 6; n++ {
+			r.cell(b.renderLand(toLandIndex(t, n))...)
+		}
+		r.WriteString("\n")
+	}
+
+	r.WriteString("\nPalisade: ")
+	for i, p := range b.Palisades {
+		if i == b.PalisadeIndex {
+			style := ansiBold
+			if b.IsBreached {
+				style = ansiBold + ansiRed
+			}
+			r.text("["+p.Label+"]", style)
+		} else {
+			r.text(" "+p.Label+" ", ansiDim)
+		}
+	}
+	if b.IsBreached {
+		r.text("  BREACHED", ansiBold+ansiRed)
+	}
+	r.WriteString("\n")
+
+	card := "none"
+	if b.Card != nil {
+		card = b.Card.String()
+	}
+	fmt.Fprintf(r, "Card: %s   Era: %s   Status: %s   APs: %d   Trade goods: %d\n",
+		card, b.CurrentEra, b.WarpathStatus, b.ActionPoints, b.TradeGoods)
+	return r.String()
+}
+
+// renderLand returns the parts of the cell showing a land's contents.
+func (b *Board) renderLand(i int) [][2]string {
+	var parts [][2]string
+	l := b.Lands[i]
+	if l.IsWilderness {
+		parts = append(parts, [2]string{"~~", ansiDim})
+	}
+	if c := b.Chiefdoms[i]; c != nil && c.Counter != nil {
+		s := fmt.Sprintf("%s%d", tradeGoodAbbrs[c.Counter.Good], c.getValue())
+		style := ""
+		if c.IsMounded {
+			s += "M"
+			style = ansiBold
+		}
+		if c.IsGreenBirdman() {
+			s += "*"
+			style += ansiGreen
+		}
+		switch {
+		case b.controls(c):
+			s += "C"
+		case c.IsControlled:
+			s += "O" // occupied
+		}
+		parts = append(parts, [2]string{s, style})
+	}
+	if b.PeacePipes[i] {
+		parts = append(parts, [2]string{"P", ansiCyan})
+	}
+	for _, h := range b.hostilesAt(i) {
+		label := "H"
+		if h.IsSpanish() {
+			label = "S"
+		}
+		parts = append(parts, [2]string{fmt.Sprintf("%s%d", label, h.BattleValue), ansiBold + ansiRed})
+	}
+	return parts
+}
@@ -59,6 +59,16 @@ type WarpathStatus struct {
 	Modifier     int
 }
 
+// modifierFor returns the modifier the status gives to rolls on a tribe's
+// warpath: the status's modifier if it names the tribe or All, and 0
+// otherwise.
+func (w WarpathStatus) modifierFor(t Tribe) int {
+	if w.Warpath == All || w.Warpath == t {
+		return w.Modifier
+	}
+	return 0
+}
+
 func (w WarpathStatus) String() string {
 	switch w.Modifier {
 	case 1:
@@ -126,6 +136,9 @@ type Chiefdom struct {
 	IsMounded    bool
 	IsControlled bool
 	CanBuild 		 bool
+	BuildReason     string    `json:",omitempty"` // why a mound can't be built here
+	BuildReasonCode ErrorCode `json:",omitempty"`
+	BuildCost    int // APs needed to build a mound here
 	LandIndex    int // index into Board.Lands
 }
 
@@ -157,21 +170,32 @@ func (c Chiefdom) IsGreenBirdman() bool {
 	return c.getCounterFace().IsGreenBird
 }
 
-// HostileMarker represents one of the six hostile markers (including the Spanish)
+// HostileMarker represents one of the six hostile markers (including the
+// Spanish).  LandIndex is the only record of where the marker is.
 type HostileMarker struct {
-	LandIndex   int // index into Board.Lands
+	Tribe       Tribe // SpanishTribe for the Spanish
+	LandIndex   int   // index into Board.Lands
 	BattleValue int
-	IsSpanish   bool // if not, Space.Warpath tells you the tribe
-	Dice        int  // only if Spanish
+	Dice        int // only if Spanish
+}
+
+func (h HostileMarker) IsSpanish() bool {
+	return h.Tribe == SpanishTribe
 }
 
 func (h HostileMarker) String() string {
-	t, i := fromLandIndex(h.LandIndex)
-	return fmt.Sprintf("%s (%d) on %d", t, h.BattleValue, i)
+	_, n := fromLandIndex(h.LandIndex)
+	return fmt.Sprintf("%s (%d) on %d", h.Tribe, h.BattleValue, n)
 }
 
 type Era int
 
+var eraNames = []string{"Hopewell", "Mississippian", "Spanish", "Generic"}
+
+func (e Era) String() string {
+	return eraNames[int(e)]
+}
+
 const (
 	Hopewell Era = iota
 	Mississippian
@@ -192,6 +216,7 @@ type Palisade struct {
 }
 
 type Board struct {
+	Turn          int
 	CurrentEra    Era
 	Card          *HistoryCard
 	ActionPoints  int
@@ -201,25 +226,36 @@ type Board struct {
 	IsBreached    bool
 	Lands         []Land
 	Chiefdoms     []*Chiefdom
-	Hostiles      []*HostileMarker
+	Hostiles      []*HostileMarker // one per tribe, in tribe order, then the Spanish once they arrive
 	PeacePipes    []bool
 	WarpathStatus WarpathStatus
 	WarpathActions map[string][]FrontEndAction
+	Economy       *EconomicBreakdown // how this turn's APs were earned
+}
+
+// EconomicBreakdown shows how the APs added in a turn's Economic Phase were
+// worked out.
+type EconomicBreakdown struct {
+	Card            int  // number of the History Card drawn
+	IsWhite         bool // whether the card's AP number is white
+	CardAP          int  // the card's AP number
+	TradeGoods      int  // trade goods earned from controlled chiefdoms
+	BaseAP          int  // APs from the AP number, before resource bonuses
+	ResourceBonuses []ResourceBonus
+	TotalAP         int
+}
+
+// ResourceBonus is the bonus earned for one of a card's resources.
+type ResourceBonus struct {
+	Good  TradeGood
+	Lands int // controlled lands producing the good, 1 AP each
 }
 
 type HistoryCard struct {
-	Number          int
-	Title           string
-	Era             Era
-	ActionPoints    int
-	IsWhite         bool
-	ResourceBonus   []TradeGood
-	Revolt          Tribe
-	AdvancingArmies []Tribe
-	Modifier        Tribe // may be None or All
-	IsAscendant     bool  // indicates if tribe's Modifier is positive or negative
-	IsAvaricia      bool
-	IsSpanish       bool
+	Number  int
+	Title   string
+	Era     Era
+	Effects []Effect // what the card does, in order; see applyEffects
 }
 
 func (h *HistoryCard) String() string {
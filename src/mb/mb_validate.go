@@ -0,0 +1,202 @@
+package mb
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	cardCount    = 50
+	counterCount = 25
+)
+
+// eraCardCounts is how many history cards each era must have.
+var eraCardCounts = map[Era]int{
+	Hopewell:      12,
+	Mississippian: 12,
+	Spanish:       2,
+	Generic:       24,
+}
+
+// ValidationErrors lists every problem Validate found in the game data.
+type ValidationErrors []error
+
+func (v ValidationErrors) Error() string {
+	lines := make([]string, len(v))
+	for i, err := range v {
+		lines[i] = err.Error()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Validate checks the game data for consistency: the card numbering and the
+// number of cards in each era, the tribes and trade goods the cards refer to,
+// the chiefdom counters, and the layout of the board.  It returns every
+// problem found, or nil if there aren't any.
+func (d *GameData) Validate() ValidationErrors {
+	var v ValidationErrors
+	v.checkCards(d.Cards, d.Counters)
+	v.checkCounters(d.Counters)
+	v.checkLands(d.Lands)
+	if len(v) == 0 {
+		return nil
+	}
+	return v
+}
+
+func (v *ValidationErrors) add(f string, args ...interface{}) {
+	*v = append(*v, fmt.Errorf(f, args...))
+}
+
+func (v *ValidationErrors) checkCards(cards Pile, counters Cup) {
+	if len(cards) != cardCount {
+		v.add("There are %d history cards; there must be %d.", len(cards), cardCount)
+	}
+
+	seen := make(map[int]bool)
+	for _, c := range cards {
+		switch {
+		case c.Number < 1 || c.Number > len(cards):
+			v.add("Card %d (%s) is numbered outside 1-%d.", c.Number, c.Title, len(cards))
+		case seen[c.Number]:
+			v.add("Card number %d is used more than once.", c.Number)
+		}
+		seen[c.Number] = true
+	}
+	for n := 1; n <= len(cards); n++ {
+		if !seen[n] {
+			v.add("There is no card %d.", n)
+		}
+	}
+
+	eras := splitByEra(cards)
+	for _, e := range []Era{Hopewell, Mississippian, Spanish, Generic} {
+		if n := len(eras[e]); n != eraCardCounts[e] {
+			v.add("There are %d %s cards; there must be %d.", n, e, eraCardCounts[e])
+		}
+	}
+
+	goods := make(map[TradeGood]bool)
+	for _, c := range counters {
+		goods[c.Good] = true
+	}
+
+	events := make(map[Event]int)
+	for _, c := range cards {
+		if n := len(c.effects(GainAPEffect)); n != 1 {
+			v.add("%s has %d AP numbers; it must have 1.", c, n)
+		}
+		for _, e := range c.Effects {
+			v.checkEffect(c, e, goods)
+			if e.Kind == SpecialEventEffect {
+				events[e.Event]++
+			}
+		}
+	}
+	avaricia, spanish := events[AvariciaEvent], events[SpanishArrivalEvent]
+	if avaricia != 1 {
+		v.add("There are %d Avaricia cards; there must be 1.", avaricia)
+	}
+	if spanish != 1 {
+		v.add("There are %d cards that bring the Spanish; there must be 1.", spanish)
+	}
+}
+
+// checkEffect checks that one of a card's effects makes sense for the card
+// and refers to tribes and trade goods that exist.
+func (v *ValidationErrors) checkEffect(c *HistoryCard, e Effect, goods map[TradeGood]bool) {
+	switch e.Kind {
+	case GainAPEffect:
+		if e.AP < 0 {
+			v.add("%s has negative action points.", c)
+		}
+		color := "white"
+		if c.Era == Generic {
+			color = "black"
+		}
+		if e.IsWhite == (c.Era == Generic) {
+			v.add("%s is a %s card, so its AP number must be %s.", c, c.Era, color)
+		}
+	case ResourceBonusEffect:
+		for _, t := range e.Goods {
+			if !goods[t] {
+				v.add("%s has a resource bonus for %s, but no counter has that trade good.", c, t)
+			}
+		}
+	case SetModifierEffect:
+		if e.Tribe > Caddo && e.Tribe != All {
+			v.add("%s modifies %s; it must modify a tribe or All.", c, e.Tribe)
+		}
+	case AdvanceArmiesEffect:
+		for _, t := range e.Tribes {
+			if t > SpanishTribe && t != CaddoOrShawnee {
+				v.add("%s advances %s, which has no army.", c, t)
+			}
+		}
+	case RevoltEffect:
+		if e.Tribe > Caddo {
+			v.add("%s has %s revolting; only the five tribes can revolt.", c, e.Tribe)
+		}
+	case SpecialEventEffect:
+		switch e.Event {
+		case AvariciaEvent, SpanishArrivalEvent:
+			if c.Era != Spanish {
+				v.add("%s triggers a Spanish event but is a %s card.", c, c.Era)
+			}
+		default:
+			v.add("%s has an unknown event %q.", c, e.Event)
+		}
+	default:
+		v.add("%s has an unknown effect %q.", c, e.Kind)
+	}
+}
+
+func (v *ValidationErrors) checkCounters(counters Cup) {
+	if len(counters) != counterCount {
+		v.add("There are %d chiefdom counters; there must be %d.", len(counters), counterCount)
+	}
+	for i, c := range counters {
+		for _, f := range []struct {
+			side string
+			face ChiefdomCounterFace
+		}{{"plain", c.Plain}, {"mounded", c.Mounded}} {
+			if f.face.Value < 1 || f.face.Value > 6 {
+				v.add("Counter %d (%s) has a %s value of %d; it must be between 1 and 6.", i+1, c.Good, f.side, f.face.Value)
+			}
+		}
+	}
+}
+
+func (v *ValidationErrors) checkLands(lands []Land) {
+	if len(lands) != LandCount {
+		v.add("There are %d lands; there must be %d.", len(lands), LandCount)
+		return
+	}
+	names := make(map[string]bool)
+	for _, t := range tribes {
+		wilderness := 0
+		for n := 1; n <= 6; n++ {
+			l := lands[toLandIndex(t, n)]
+			if l.Name == "" {
+				v.add("The %s warpath has no land in space %d.", t, n)
+				continue
+			}
+			if l.Warpath != t || l.Space != n || l.Index != toLandIndex(t, n) {
+				v.add("%s is in the wrong place on the board.", l.Name)
+			}
+			if names[strings.ToLower(l.Name)] {
+				v.add("There is more than one land named %s.", l.Name)
+			}
+			names[strings.ToLower(l.Name)] = true
+			if l.IsWilderness {
+				wilderness++
+				if n == 6 {
+					v.add("The %s homeland cannot be wilderness.", t)
+				}
+			}
+		}
+		if wilderness != 1 {
+			v.add("The %s warpath has %d wilderness lands; it must have 1.", t, wilderness)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package mb
+
+import (
+	"strings"
+	"testing"
+)
+
+// testGameData returns the embedded game data, which Validate accepts.
+func testGameData(t *testing.T) *GameData {
+	t.Helper()
+	d, err := LoadGameData("", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if errs := d.Validate(); errs != nil {
+		t.Fatalf("the embedded data doesn't validate:\n%v", errs)
+	}
+	return d
+}
+
+// checkValidation checks that Validate finds exactly the given problems.
+func checkValidation(t *testing.T, what string, d *GameData, want ...string) {
+	t.Helper()
+	errs := d.Validate()
+	if len(errs) != len(want) {
+		t.Errorf("%s: got %d problems; want %d:\n%v", what, len(errs), len(want), errs)
+		return
+	}
+	for i, w := range want {
+		if !strings.Contains(errs[i].Error(), w) {
+			t.Errorf("%s: problem %d is %q; want %q", what, i, errs[i], w)
+		}
+	}
+}
+
+func TestValidateCounters(t *testing.T) {
+	d := testGameData(t)
+	d.Counters[0].Plain.Value = 0
+	d.Counters[1].Mounded.Value = 7
+	checkValidation(t, "bad values", d,
+		"Counter 1 (Hides) has a plain value of 0",
+		"Counter 2 (Hides) has a mounded value of 7")
+
+	d = testGameData(t)
+	d.Counters = d.Counters[1:]
+	checkValidation(t, "missing counter", d, "There are 24 chiefdom counters; there must be 25.")
+}
+
+func TestValidateLands(t *testing.T) {
+	d := testGameData(t)
+	d.Lands = d.Lands[:LandCount-1]
+	checkValidation(t, "missing land", d, "There are 29 lands; there must be 30.")
+
+	d = testGameData(t)
+	d.Lands[toLandIndex(Caddo, 2)].IsWilderness = false
+	checkValidation(t, "no wilderness", d, "The Caddo warpath has 0 wilderness lands; it must have 1.")
+
+	d = testGameData(t)
+	d.Lands[toLandIndex(Caddo, 1)].IsWilderness = true
+	checkValidation(t, "two wilderness", d, "The Caddo warpath has 2 wilderness lands; it must have 1.")
+
+	d = testGameData(t)
+	d.Lands[toLandIndex(Caddo, 6)].IsWilderness = true
+	checkValidation(t, "wilderness homeland", d,
+		"The Caddo homeland cannot be wilderness.",
+		"The Caddo warpath has 2 wilderness lands")
+}
+
+func TestValidateCardEffects(t *testing.T) {
+	tests := []struct {
+		number int
+		effect Effect
+		want   string
+	}{
+		{1, Effect{Kind: GainAPEffect, AP: 2, IsWhite: true}, "has 2 AP numbers"},
+		{1, Effect{Kind: RevoltEffect, Tribe: SpanishTribe}, "only the five tribes can revolt"},
+		{1, Effect{Kind: SetModifierEffect, Tribe: CaddoOrShawnee}, "it must modify a tribe or All"},
+		{1, Effect{Kind: AdvanceArmiesEffect, Tribes: []Tribe{None}}, "advances None, which has no army"},
+		{1, Effect{Kind: SpecialEventEffect, Event: AvariciaEvent}, "triggers a Spanish event but is a Hopewell card"},
+		{1, Effect{Kind: "Famine"}, `has an unknown effect "Famine"`},
+	}
+	for _, tt := range tests {
+		d := testGameData(t)
+		c := d.Cards[tt.number-1]
+		c.Effects = append(c.Effects, tt.effect)
+		want := []string{tt.want}
+		if tt.effect.Kind == SpecialEventEffect {
+			want = append(want, "There are 2 Avaricia cards")
+		}
+		checkValidation(t, tt.effect.String(), d, want...)
+	}
+
+	d := testGameData(t)
+	d.Cards[26].Effects[0] = Effect{Kind: GainAPEffect, AP: -1}
+	checkValidation(t, "negative AP", d, "has negative action points")
+
+	d = testGameData(t)
+	d.Cards[0].Effects[0].IsWhite = false
+	checkValidation(t, "black Hopewell AP", d, "is a Hopewell card, so its AP number must be white")
+
+	d = testGameData(t)
+	for _, c := range d.Counters {
+		if c.Good == Hides {
+			c.Good = Mica
+		}
+	}
+	checkValidation(t, "missing trade good", d, "resource bonus for Hides, but no counter has that trade good")
+}
@@ -2,29 +2,289 @@ package main
 
 import (
 	"bufio"
+	"console"
+	"flag"
 	"fmt"
+	"log"
 	"mb"
 	"os"
 	"strings"
+	"text/tabwriter"
+	"time"
 
 //	"encoding/json"
 )
 
+var (
+	difficulty   = flag.String("difficulty", "normal", "rule preset: easy, normal or hard")
+	cardsFile    = flag.String("cards", "", "CSV or JSON file of history cards to use instead of the built-in cards")
+	countersFile = flag.String("counters", "", "CSV or JSON file of chiefdom counters to use instead of the built-in counters")
+	boardFile    = flag.String("board", "", "CSV or JSON file of lands to use instead of the built-in board")
+	validate     = flag.Bool("validate", false, "check the game data for problems instead of playing")
+	seed         = flag.Int64("seed", 0, "random number seed; 0 picks one from the clock")
+	script       = flag.String("script", "", "file of commands to play without prompting, or - for standard input")
+	debug        = flag.Bool("debug", false, "check the board after every step; a move that breaks the rules is reported and undone")
+)
+
+// validateData reports every problem in the game data, exiting with a
+// nonzero status if there are any.
+func validateData() {
+	d, err := mb.LoadGameData(*cardsFile, *countersFile, *boardFile)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	errs := d.Validate()
+	for _, err := range errs {
+		fmt.Println(err)
+	}
+	if len(errs) > 0 {
+		fmt.Printf("%d problems found.\n", len(errs))
+		os.Exit(1)
+	}
+	fmt.Printf("OK: %d cards, %d counters, %d lands.\n", len(d.Cards), len(d.Counters), len(d.Lands))
+}
+
+// isTerminal reports whether f is a terminal that can show colors.
+func isTerminal(f *os.File) bool {
+	fi, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return fi.Mode()&os.ModeCharDevice != 0 && os.Getenv("TERM") != "dumb"
+}
+
 func main() {
-	g := mb.NewGame()
+	flag.Parse()
+	if *validate {
+		validateData()
+		return
+	}
+	o, err := mb.NewGameOptions(mb.Difficulty(*difficulty))
+	if err != nil {
+		log.Fatal(err)
+	}
+	o.CardsFile, o.CountersFile, o.BoardFile = *cardsFile, *countersFile, *boardFile
+	o.Seed = *seed
+	g, err := mb.NewGame(o)
+	if err != nil {
+		log.Fatal(err)
+	}
+	g.Debug = *debug
+	g.Rollback = true
+	if *script != "" {
+		os.Exit(runScript(g, *script))
+	}
 	g.LogToConsole = true
 	g.StartGame()
-	for g.Response != nil {
-		g.Request.Input = ""
+
+	con := console.New(os.Stdin, os.Stdout)
+	con.Complete = g.Complete
+	showBoard := true
+	color := isTerminal(os.Stdout)
+	show := func() {
+		if showBoard {
+			fmt.Print("\n\n" + g.Board.Render(color))
+		}
 		if g.Response.Error != nil {
 			fmt.Printf("\nError: %s\n", g.Response.Error)
 		}
-		reader := bufio.NewReader(os.Stdin)
-		// this is pretty hacky, but it'll do for keyboard input
-		fmt.Print("\n" + g.Response.Prompt + "> ")
-		s, _ := reader.ReadString('\n')
-		line := strings.Split(s, "\r")[0]
+	}
+	if g.Response != nil {
+		show()
+	}
+	for g.Response != nil {
+		line, err := con.ReadLine("\n" + string(g.Response.Prompt) + "> ")
+		if err != nil {
+			// end of input or Ctrl-C
+			break
+		}
+		// console commands don't go to the game
+		switch strings.ToLower(strings.TrimSpace(line)) {
+		case "board":
+			showBoard = !showBoard
+			if showBoard {
+				fmt.Print("\n" + g.Board.Render(color))
+			}
+			continue
+		case "help", "?":
+			printHelp()
+			continue
+		case "advise":
+			printAdvice(g)
+			continue
+		case "cup":
+			printCup(g)
+			continue
+		case "odds":
+			printOdds(g)
+			continue
+		case "threats":
+			printThreats(g)
+			continue
+		}
 		g.HandleRequest(mb.Request{Input: mb.Input(line)})
+		if g.Response != nil {
+			show()
+		}
 	}
 	fmt.Println("\n\nEnd of game")
 }
+
+// runScript plays the commands in a script file, one per line, ignoring
+// blank lines and lines starting with #.  It prints the final log and board
+// and returns the exit status: 0 if every command was played, 1 if the
+// script couldn't be read or a command broke the rules.
+func runScript(g *mb.Game, name string) int {
+	f := os.Stdin
+	if name != "-" {
+		var err error
+		if f, err = os.Open(name); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		defer f.Close()
+	}
+
+	status := 0
+	g.StartGame()
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if g.Response == nil {
+			fmt.Fprintf(os.Stderr, "%s:%d: the game is over, but the script continues with %q\n", name, n, line)
+			status = 1
+			break
+		}
+		g.HandleRequest(mb.Request{Input: mb.Input(line)})
+		if g.Response != nil && g.Response.Error != nil {
+			fmt.Fprintf(os.Stderr, "%s:%d: %q: %s\n", name, n, line, g.Response.Error)
+			status = 1
+			break
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		status = 1
+	}
+
+	for _, line := range g.Log {
+		if line != "" {
+			fmt.Println(line)
+		}
+	}
+	fmt.Print("\n" + g.Board.Render(false))
+	if g.Response != nil {
+		fmt.Printf("\n%s>\n", g.Response.Prompt)
+	} else {
+		fmt.Println("\nEnd of game")
+	}
+	return status
+}
+
+// printHelp lists the actions and console commands.
+func printHelp() {
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "\nAction\tName\tCost\tTarget\tDescription")
+	for _, as := range mb.Actions() {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", as.Name, as.Abbr, as.Cost, as.Target, as.Description)
+	}
+	fmt.Fprintln(w, "\nCommand\t\t\t\tDescription")
+	fmt.Fprintln(w, "board\t\t\t\tShow or hide the board")
+	fmt.Fprintln(w, "advise\t\t\t\tRank the actions you can take by expected score")
+	fmt.Fprintln(w, "cup\t\t\t\tList the chiefdom counters left in the cup")
+	fmt.Fprintln(w, "odds\t\t\t\tShow the odds for the next counter and History Card")
+	fmt.Fprintln(w, "threats\t\t\t\tForecast the hostile armies' advances on Cahokia")
+	fmt.Fprintln(w, "help\t\t\t\tShow this help")
+	w.Flush()
+	fmt.Println("\nActions can be given by code or name, e.g. inc or Incorporate.  Targets can")
+	fmt.Println("be abbreviated a word at a time, e.g. mnd bot cr for Bottle Creek, or quoted.")
+	fmt.Println("Tab completes actions, tribes and lands; the up and down arrows recall")
+	fmt.Println("earlier commands; Ctrl-D ends the game.")
+}
+
+// printCup lists the chiefdom counters left in the cup.
+func printCup(g *mb.Game) {
+	cup := g.CupContents()
+	if len(cup) == 0 {
+		fmt.Println("\nThe cup is empty.")
+		return
+	}
+	fmt.Printf("\n%d counters in the cup:\n", len(cup))
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "Good\tPlain\tMounded")
+	face := func(f mb.ChiefdomCounterFace) string {
+		if f.IsGreenBird {
+			return fmt.Sprintf("%d B", f.Value)
+		}
+		return fmt.Sprintf("%d R", f.Value)
+	}
+	for _, c := range cup {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", c.Good, face(c.Plain), face(c.Mounded))
+	}
+	w.Flush()
+}
+
+// printOdds shows the chances of what the next counter drawn and the next
+// History Card will be.
+func printOdds(g *mb.Game) {
+	o := g.Odds(false)
+	pct := func(p float64) string {
+		if p == 0 {
+			return "-"
+		}
+		return fmt.Sprintf("%.0f%%", 100*p)
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintf(w, "\nNext counter (%d in the cup):\n", o.Cup.Counters)
+	for _, c := range o.Cup.Goods {
+		fmt.Fprintf(w, "  %s\t%s\n", c.Good, pct(c.Chance))
+	}
+	for _, c := range o.Cup.Values {
+		fmt.Fprintf(w, "  Value %d\t%s\n", c.Value, pct(c.Chance))
+	}
+	fmt.Fprintf(w, "  Green Birdman\t%s\n", pct(o.Cup.GreenBirdman))
+	fmt.Fprintf(w, "\nNext History Card (%d possible):\n", o.Card.Cards)
+	fmt.Fprintln(w, "  Tribe\tRevolt\tAdvance\tAscendant\tDeclining")
+	for _, t := range o.Card.Tribes {
+		fmt.Fprintf(w, "  %s\t%s\t%s\t%s\t%s\n", t.Tribe, pct(t.Revolt), pct(t.Advance), pct(t.Ascendant), pct(t.Declining))
+	}
+	w.Flush()
+}
+
+// printThreats shows how often each army is expected to advance over the
+// next few turns and the chance of it reaching Cahokia.
+func printThreats(g *mb.Game) {
+	f := g.Forecast(mb.DefaultForecastTurns, mb.DefaultForecastRollouts, time.Now().UnixNano())
+	fmt.Printf("\nOver the next %d turns (%d shuffles of the deck):\n", f.Turns, f.Rollouts)
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "Army\tSpace\tAdvances\tReach\t")
+	for _, t := range f.Threats {
+		if t.Space == 0 {
+			fmt.Fprintf(w, "%s\t-\t-\t-\t\n", t.Tribe)
+			continue
+		}
+		fmt.Fprintf(w, "%s\t%d\t%.1f\t%.0f%%\t\n", t.Tribe, t.Space, t.Advances, 100*t.Reach)
+	}
+	w.Flush()
+}
+
+// printAdvice ranks the legal actions by the score they're expected to
+// lead to.
+func printAdvice(g *mb.Game) {
+	fmt.Printf("\nPlaying out each action %d times...\n", mb.DefaultRollouts)
+	advice, err := g.Advise(mb.DefaultRollouts, time.Now().UnixNano())
+	if err != nil {
+		fmt.Printf("\nError: %s\n", err)
+		return
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "\nAction\tExpected score\tWin rate\tFailed")
+	for _, a := range advice {
+		fmt.Fprintf(w, "%s\t%.1f\t%.0f%%\t%d/%d\n", a.Input, a.ExpectedScore, 100*a.WinRate, a.Failed, a.Rollouts)
+	}
+	w.Flush()
+}
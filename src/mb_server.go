@@ -3,13 +3,17 @@ package main
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"log"
 	"net/http"
 	"mb"
+	"strconv"
 	"strings"
+	"time"
 )
 
 var g *mb.Game
@@ -53,23 +57,39 @@ func mbBoardHandler(w http.ResponseWriter, q *http.Request) {
 				log.Println(err)			
 			} else {
 				g.HandleRequest(*r)
+				if e := g.Failed(); e != nil {
+					log.Printf("%s\n%s", e, e.Stack)
+				}
 			}			
 		}
 	}
 
+	writeBoard(w)
+}
+
+// writeBoard writes the board, options, prompt and any error as JSON.
+func writeBoard(w http.ResponseWriter) {
 	type response struct {
 		Board mb.Board
+		Options mb.GameOptions
+		Cup []mb.ChiefdomCounter // what's left in the cup, in no particular order
 		Error string
+		RuleError *mb.RuleError `json:",omitempty"`
+		InternalError *mb.InternalError `json:",omitempty"`
+		Threats *mb.Forecast
 		Prompt string
 	}
-	r := &response{Board: g.Board}
+	r := &response{Board: g.Board, Options: g.Options, Cup: g.CupContents()}
 
 	if g.Response != nil {
 		r.Prompt = string(g.Response.Prompt)
 		if g.Response.Error != nil {
 			r.Error = g.Response.Error.Error()
+			errors.As(g.Response.Error, &r.RuleError)
 		}
+		r.InternalError = g.Failed()
 	}
+	r.Threats = g.Forecast(mb.DefaultForecastTurns, mb.DefaultForecastRollouts, time.Now().UnixNano())
 	
 	b, err := json.Marshal(r)
 	if err != nil {
@@ -79,6 +99,109 @@ func mbBoardHandler(w http.ResponseWriter, q *http.Request) {
 	}
 }
 
+// mbHintHandler suggests the computer player's next move without making it.
+func mbHintHandler(w http.ResponseWriter, q *http.Request) {
+	type hint struct {
+		Input string
+	}
+	var h hint
+	if r, ok := g.NextRequest(mb.HeuristicPlayer{}); ok {
+		h.Input = string(r.Input)
+	}
+	b, err := json.Marshal(h)
+	if err != nil {
+		fmt.Fprint(w, err)
+	} else {
+		fmt.Fprint(w, bytes.NewBuffer(b).String())
+	}
+}
+
+// mbAutoHandler has the computer player make the next move when POSTed to,
+// then returns the board just as mbBoardHandler does.
+func mbAutoHandler(w http.ResponseWriter, q *http.Request) {
+	if q.Method == "POST" {
+		if r, ok := g.NextRequest(mb.HeuristicPlayer{}); ok {
+			g.HandleRequest(r)
+			if e := g.Failed(); e != nil {
+				log.Printf("%s\n%s", e, e.Stack)
+			}
+		}
+	}
+	writeBoard(w)
+}
+
+// maxAdviseRollouts caps the rollouts parameter of an advice request, since
+// each rollout plays a whole game for each legal action.
+const maxAdviseRollouts = 200
+
+// intParam reads a whole number parameter, holding it to at most max.  A
+// missing or bad value reads as 0, which asks for the default.
+func intParam(q *http.Request, name string, max int) int {
+	n, _ := strconv.Atoi(q.FormValue(name))
+	if n > max {
+		n = max
+	}
+	return n
+}
+
+// mbAdviseHandler ranks the legal actions by expected final score.  The
+// rollouts parameter sets how many games are played out for each action, up
+// to maxAdviseRollouts.
+func mbAdviseHandler(w http.ResponseWriter, q *http.Request) {
+	rollouts := intParam(q, "rollouts", maxAdviseRollouts)
+	advice, err := g.Advise(rollouts, time.Now().UnixNano())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	b, err := json.Marshal(advice)
+	if err != nil {
+		fmt.Fprint(w, err)
+	} else {
+		fmt.Fprint(w, bytes.NewBuffer(b).String())
+	}
+}
+
+// The most turns and deck shuffles a threats request can ask for.  No game
+// lasts more than maxForecastTurns turns.
+const (
+	maxForecastTurns    = 50
+	maxForecastRollouts = 5000
+)
+
+// mbThreatsHandler forecasts the hostile armies' advances on Cahokia.  The
+// turns and rollouts parameters set how many turns ahead to look and how
+// many times to shuffle the deck, up to maxForecastTurns and
+// maxForecastRollouts.
+func mbThreatsHandler(w http.ResponseWriter, q *http.Request) {
+	turns := intParam(q, "turns", maxForecastTurns)
+	rollouts := intParam(q, "rollouts", maxForecastRollouts)
+	b, err := json.Marshal(g.Forecast(turns, rollouts, time.Now().UnixNano()))
+	if err != nil {
+		fmt.Fprint(w, err)
+	} else {
+		fmt.Fprint(w, bytes.NewBuffer(b).String())
+	}
+}
+
+// mbOddsHandler reports the chances of what the next counter and History
+// Card will be.  Setting the cheat parameter bases them on what will
+// actually be drawn, for testing; it's refused unless the server was started
+// with -cheat.
+func mbOddsHandler(w http.ResponseWriter, q *http.Request) {
+	cheat, _ := strconv.ParseBool(q.FormValue("cheat"))
+	if cheat && !*allowCheat {
+		http.Error(w, "Cheating is only allowed when the server is started with -cheat.", http.StatusForbidden)
+		return
+	}
+	b, err := json.Marshal(g.Odds(cheat))
+	if err != nil {
+		fmt.Fprint(w, err)
+	} else {
+		fmt.Fprint(w, bytes.NewBuffer(b).String())
+	}
+}
+
 func mbLogHandler(w http.ResponseWriter, r *http.Request) {
 	b, err := json.Marshal(g.Log)
 	if err != nil {
@@ -88,12 +211,38 @@ func mbLogHandler(w http.ResponseWriter, r *http.Request) {
 	}	
 }
 
+var (
+	difficulty   = flag.String("difficulty", "normal", "rule preset: easy, normal or hard")
+	cardsFile    = flag.String("cards", "", "CSV or JSON file of history cards to use instead of the built-in cards")
+	countersFile = flag.String("counters", "", "CSV or JSON file of chiefdom counters to use instead of the built-in counters")
+	boardFile    = flag.String("board", "", "CSV or JSON file of lands to use instead of the built-in board")
+	seed         = flag.Int64("seed", 0, "random number seed; 0 picks one from the clock")
+	debug        = flag.Bool("debug", false, "check the board after every step; a move that breaks the rules is reported and undone")
+	allowCheat   = flag.Bool("cheat", false, "allow /mb/odds/?cheat=true, which gives away the next counter and card, for testing")
+)
+
 func main() {
-	g = mb.NewGame()
+	flag.Parse()
+	o, err := mb.NewGameOptions(mb.Difficulty(*difficulty))
+	if err != nil {
+		log.Fatal(err)
+	}
+	o.CardsFile, o.CountersFile, o.BoardFile = *cardsFile, *countersFile, *boardFile
+	o.Seed = *seed
+	if g, err = mb.NewGame(o); err != nil {
+		log.Fatal(err)
+	}
+	g.Debug = *debug
+	g.Rollback = true
 	g.StartGame()
 	
     http.HandleFunc("/", appHandler)
     http.HandleFunc("/mb/board/", mbBoardHandler)
     http.HandleFunc("/mb/log/", mbLogHandler)
+    http.HandleFunc("/mb/hint/", mbHintHandler)
+    http.HandleFunc("/mb/auto/", mbAutoHandler)
+    http.HandleFunc("/mb/advise/", mbAdviseHandler)
+    http.HandleFunc("/mb/odds/", mbOddsHandler)
+    http.HandleFunc("/mb/threats/", mbThreatsHandler)
     http.ListenAndServe(":8080", nil)
 }
\ No newline at end of file
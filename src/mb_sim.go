@@ -0,0 +1,198 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"mb"
+	"os"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+var (
+	games       = flag.Int("games", 1000, "number of games to play")
+	policyName  = flag.String("policy", "random", "how to choose moves: random or heuristic (also called greedy)")
+	parallel    = flag.Int("parallel", runtime.NumCPU(), "number of games to play at once")
+	seed        = flag.Int64("seed", 0, "seed for the first game; 0 picks one from the clock")
+	difficulty  = flag.String("difficulty", "normal", "rule preset: easy, normal or hard")
+	maxRequests = flag.Int("maxrequests", 10000, "give up on a game after this many moves")
+	debug       = flag.Bool("debug", false, "check the board after every step; a broken rule ends the game")
+)
+
+// policies make the player for a game, given the game's seed.
+var policies = map[string]func(seed int64) mb.Player{
+	"random":    func(seed int64) mb.Player { return mb.NewRandomPlayer(seed) },
+	"heuristic": func(int64) mb.Player { return mb.HeuristicPlayer{} },
+	"greedy":    func(int64) mb.Player { return mb.HeuristicPlayer{} }, // the heuristic policy's old name
+}
+
+// outcome is the result of one simulated game.
+type outcome struct {
+	mb.Result
+	Error string // set if the game couldn't be finished
+}
+
+// play plays one game to the end with the given seed.
+func play(o mb.GameOptions, newPlayer func(int64) mb.Player, seed int64) outcome {
+	o.Seed = seed
+	g, err := mb.NewGame(o)
+	if err != nil {
+		log.Fatal(err)
+	}
+	g.Debug = *debug
+	p := newPlayer(seed)
+	g.StartGame()
+	for n := 0; !g.IsOver(); n++ {
+		if n == *maxRequests {
+			return outcome{Result: g.Result(), Error: fmt.Sprintf("gave up after %d moves", n)}
+		}
+		q, ok := g.NextRequest(p)
+		if !ok {
+			return outcome{Result: g.Result(), Error: fmt.Sprintf("no legal moves at %q", g.Response.Prompt)}
+		}
+		g.HandleRequest(q)
+		if e := g.Failed(); e != nil {
+			return outcome{Result: g.Result(), Error: fmt.Sprintf("panic in %s: %s", e.State, e.Message)}
+		}
+	}
+	return outcome{Result: g.Result()}
+}
+
+func main() {
+	flag.Parse()
+	newPlayer, ok := policies[*policyName]
+	if !ok {
+		log.Fatalf("Unknown policy %q; must be random or heuristic.", *policyName)
+	}
+	o, err := mb.NewGameOptions(mb.Difficulty(*difficulty))
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *seed == 0 {
+		*seed = time.Now().UnixNano()
+	}
+
+	seeds := make(chan int64)
+	outcomes := make(chan outcome)
+	var wg sync.WaitGroup
+	for i := 0; i < *parallel; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for s := range seeds {
+				outcomes <- play(o, newPlayer, s)
+			}
+		}()
+	}
+	go func() {
+		for i := 0; i < *games; i++ {
+			seeds <- *seed + int64(i)
+		}
+		close(seeds)
+		wg.Wait()
+		close(outcomes)
+	}()
+
+	var results []outcome
+	for out := range outcomes {
+		results = append(results, out)
+	}
+	report(results)
+}
+
+// report prints the win rate, score distribution, game length and causes of
+// the end of the games, with the card and revolt each game ended on.  Games
+// that failed with an error are left out of the win rate, length and scores,
+// which would otherwise count a game cut short as a loss, and are counted
+// separately.
+func report(results []outcome) {
+	n := len(results)
+	if n == 0 {
+		return
+	}
+	var wins, turns, failed int
+	var scores []int
+	causes := make(map[string]int)
+	cards := make(map[string]int)
+	revolts := make(map[string]int)
+	for _, r := range results {
+		cause := r.Cause
+		if r.Error != "" {
+			cause = r.Error
+			failed++
+		} else {
+			if r.Won {
+				wins++
+			}
+			turns += r.Turns
+			scores = append(scores, r.Score)
+		}
+		causes[cause]++
+		card := "No card (the History Deck ran out)"
+		if r.Card != 0 {
+			card = fmt.Sprintf("Card %d", r.Card)
+		}
+		cards[card]++
+		revolt := "No revolt"
+		if r.Revolt != mb.None {
+			revolt = fmt.Sprintf("%s revolt", r.Revolt)
+		}
+		revolts[revolt]++
+	}
+	sort.Ints(scores)
+
+	fmt.Printf("Games:          %d (policy %s, %s, first seed %d)\n", n, *policyName, *difficulty, *seed)
+	fmt.Printf("Failed games:   %d (left out of the figures below)\n", failed)
+	if finished := len(scores); finished > 0 {
+		fmt.Printf("Win rate:       %.1f%%\n", 100*float64(wins)/float64(finished))
+		fmt.Printf("Average length: %.1f turns\n", float64(turns)/float64(finished))
+		total := 0
+		for _, s := range scores {
+			total += s
+		}
+		fmt.Printf("Score:          min %d, median %d, mean %.1f, max %d\n",
+			scores[0], scores[finished/2], float64(total)/float64(finished), scores[finished-1])
+
+		fmt.Println("\nScore distribution:")
+		counts := make(map[int]int)
+		for _, s := range scores {
+			counts[s]++
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', tabwriter.AlignRight)
+		for s := scores[0]; s <= scores[finished-1]; s++ {
+			if counts[s] > 0 {
+				bar := strings.Repeat("#", (counts[s]*50+finished-1)/finished)
+				fmt.Fprintf(w, "%d\t%d\t%s\t\n", s, counts[s], bar)
+			}
+		}
+		w.Flush()
+	}
+
+	printTally("How the games ended:", causes, n)
+	printTally("History Card in play at the end:", cards, n)
+	printTally("Revolt on the last turn:", revolts, n)
+}
+
+// printTally prints how many of the n games fall under each key, most
+// frequent first.
+func printTally(title string, counts map[string]int, n int) {
+	fmt.Println("\n" + title)
+	var keys []string
+	for k := range counts {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if counts[keys[i]] != counts[keys[j]] {
+			return counts[keys[i]] > counts[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+	for _, k := range keys {
+		fmt.Printf("%6d  %5.1f%%  %s\n", counts[k], 100*float64(counts[k])/float64(n), k)
+	}
+}